@@ -7,7 +7,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -46,7 +47,19 @@ type chatRequest struct {
 	input              string
 	imageURLs          []string
 	additionalMessages [][]Message
-	timeout            *time.Duration // Timeout específico para esta llamada
+	timeout            *time.Duration  // Timeout específico para esta llamada
+	sessionID          string          // Conversation session; empty selects the default session
+	metadata           map[string]any  // Caller-defined values exposed to system prompt providers
+	extraTools         []Tool          // Tools available for this request only
+	ephemeral          bool            // Whether memory is left untouched
+	dryRun             bool            // Whether to stop before the first provider call
+	inputGuardrails    []Guardrail     // Guardrails added to the agent's input guardrails
+	outputGuardrails   []Guardrail     // Guardrails added to the agent's output guardrails
+	eventHandlers      []EventHandler  // Handlers added to the agent's event handlers
+	runID              string          // Checkpoint run ID
+	checkpoints        CheckpointStore // Overrides the agent's checkpoint store
+	enabledTools       []string        // Tool or toolkit names offered exclusively; empty offers all
+	disabledTools      []string        // Tool or toolkit names withheld
 }
 
 // WithUserName sets the user name for the chat request.
@@ -87,6 +100,34 @@ func WithChatTimeout(timeout time.Duration) ChatOption {
 	}
 }
 
+// WithSessionID selects the conversation session for the chat request.
+// Each session has its own isolated history and chats on the same session are serialized,
+// so a single agent can serve many users concurrently. An empty ID selects the default session.
+func WithSessionID(sessionID string) ChatOption {
+	return func(r *chatRequest) {
+		r.sessionID = sessionID
+	}
+}
+
+// WithMetadata attaches a key/value pair to the chat request.
+// Metadata is passed to system prompt providers through PromptContext.
+func WithMetadata(key string, value any) ChatOption {
+	return func(r *chatRequest) {
+		if r.metadata == nil {
+			r.metadata = make(map[string]any)
+		}
+		r.metadata[key] = value
+	}
+}
+
+// WithExtraTools makes additional tools available for this chat request only.
+// An extra tool replaces an agent tool with the same name for the duration of the request.
+func WithExtraTools(tools ...Tool) ChatOption {
+	return func(r *chatRequest) {
+		r.extraTools = append(r.extraTools, tools...)
+	}
+}
+
 // Agent defines the interface for processing inputs and managing tools.
 type Agent interface {
 	Chat(ctx context.Context, options ...ChatOption) (string, error)
@@ -95,17 +136,41 @@ type Agent interface {
 
 // agent holds the implementation of the Agent interface.
 type agent struct {
-	client         LLMClient
-	name           string
-	description    string
-	systemPrompt   string
-	tools          map[string]Tool
-	memory         Memory
-	model          string
-	mutex          sync.RWMutex
-	temperature    float32
-	responseFormat *ResponseFormat
-	timeout        time.Duration // Timeout configurable para el agente
+	client           LLMClient
+	name             string
+	description      string
+	systemPrompt     string
+	promptProvider   SystemPromptProvider // Renders the system prompt per chat; overrides systemPrompt
+	tools            map[string]Tool
+	memory           Memory        // Memory of the default session
+	memoryFactory    MemoryFactory // Creates the memory of each new session
+	sessions         map[string]*session
+	sessionsMutex    sync.Mutex
+	model            string
+	mutex            sync.RWMutex
+	temperature      float32
+	responseFormat   *ResponseFormat
+	timeout          time.Duration   // Timeout configurable para el agente
+	hooks            hookChain       // Lifecycle hooks run around each stage of a chat turn
+	tracer           Tracer          // Tracer used to emit spans for chats, LLM calls and tool calls
+	continueOnLength bool            // Whether length-truncated answers are handled (continued or reported)
+	maxContinuations int             // How many continuation requests may be sent per turn
+	logger           *slog.Logger    // Structured logger; discards records by default
+	debugLogging     bool            // Whether message content may be included in logs
+	strategy         Strategy        // How the agent reasons and uses tools within a turn
+	reflection       *reflection     // Critique and revision of answers; nil disables reflection
+	atomicTurns      bool            // Whether memory writes are committed only when a turn succeeds
+	inputGuardrails  []Guardrail     // Checks run on the user's input before it is stored
+	outputGuardrails []Guardrail     // Checks run on every final answer before it is stored
+	guardrailRetries int             // How many times an answer may be regenerated for output guardrails
+	eventHandlers    []EventHandler  // Receive the events of every chat turn
+	checkpoints      CheckpointStore // Saves the progress of chat turns; nil disables checkpoints
+	cacheBreakpoints bool            // Whether requests mark cache breakpoints for explicit prompt caching
+	examples         ExampleSelector // Chooses the few-shot examples of each turn; nil shows none
+	toolSelector     ToolSelector    // Chooses the tools offered in each turn; nil offers all
+
+	validateArguments bool // Whether tool arguments are checked against the tool's schema before execution
+	repairArguments   bool // Whether malformed tool arguments are repaired before validation
 }
 
 // AgentOption defines a function that configures an Agent.
@@ -149,7 +214,7 @@ func WithSystemPrompt(prompt string) AgentOption {
 	}
 }
 
-// WithMemory sets the memory implementation for the agent.
+// WithMemory sets the memory implementation for the agent's default session.
 func WithMemory(memory Memory) AgentOption {
 	return func(a *agent) error {
 		if memory == nil {
@@ -193,17 +258,36 @@ func WithTimeout(timeout time.Duration) AgentOption {
 	}
 }
 
+// WithCacheBreakpoints marks the system prompt and the last message of the conversation as cache
+// breakpoints (Message.CacheBreakpoint) in every request, so that providers with explicit prompt
+// caching can reuse the stable prefix of the conversation between rounds and turns.
+func WithCacheBreakpoints() AgentOption {
+	return func(a *agent) error {
+		a.cacheBreakpoints = true
+		return nil
+	}
+}
+
+// validateTool checks that a tool can be registered and returns its name.
+func validateTool(tool Tool) (string, error) {
+	if tool == nil {
+		return "", errors.New("tool cannot be nil")
+	}
+	def := tool.GetDefinition()
+	if def.Name == "" {
+		return "", errors.New("tool name cannot be empty")
+	}
+	return def.Name, nil
+}
+
 // WithTool adds a tool to the agent.
 func WithTool(tool Tool) AgentOption {
 	return func(a *agent) error {
-		if tool == nil {
-			return errors.New("tool cannot be nil")
-		}
-		def := tool.GetDefinition()
-		if def.Name == "" {
-			return errors.New("tool name cannot be empty")
+		name, err := validateTool(tool)
+		if err != nil {
+			return err
 		}
-		a.tools[def.Name] = tool
+		a.tools[name] = tool
 		return nil
 	}
 }
@@ -212,39 +296,57 @@ func WithTool(tool Tool) AgentOption {
 func WithTools(tools ...Tool) AgentOption {
 	return func(a *agent) error {
 		for _, tool := range tools {
-			if tool == nil {
-				return errors.New("tool cannot be nil")
-			}
-			def := tool.GetDefinition()
-			if def.Name == "" {
-				return errors.New("tool name cannot be empty")
+			name, err := validateTool(tool)
+			if err != nil {
+				return err
 			}
-			a.tools[def.Name] = tool
+			a.tools[name] = tool
 		}
 		return nil
 	}
 }
 
-// WithJSONResponseFormat configures the agent to use a JSON schema for response formatting.
-func WithJSONResponseFormat(schemaName string, structSchema any) AgentOption {
+// newJSONResponseFormat builds a strict JSON schema response format from a struct.
+func newJSONResponseFormat(schemaName string, structSchema any) (*ResponseFormat, error) {
+	if schemaName == "" {
+		return nil, errors.New("schema name cannot be empty")
+	}
+
+	schema, err := GenerateRawSchema(structSchema)
+	if err != nil {
+		return nil, fmt.Errorf("error generating schema: %w", err)
+	}
+
+	return &ResponseFormat{
+		Type: "json_schema",
+		JSONSchema: &JSONSchema{
+			Name:   schemaName,
+			Schema: schema,
+			Strict: true,
+		},
+	}, nil
+}
+
+// WithResponseFormat sets the response format requested from the model, for example a JSON schema
+// that is not generated from a Go struct.
+func WithResponseFormat(format *ResponseFormat) AgentOption {
 	return func(a *agent) error {
-		if schemaName == "" {
-			return errors.New("schema name cannot be empty")
+		if format == nil {
+			return errors.New("response format cannot be nil")
 		}
+		a.responseFormat = format
+		return nil
+	}
+}
 
-		schema, err := GenerateRawSchema(structSchema)
+// WithJSONResponseFormat configures the agent to use a JSON schema for response formatting.
+func WithJSONResponseFormat(schemaName string, structSchema any) AgentOption {
+	return func(a *agent) error {
+		format, err := newJSONResponseFormat(schemaName, structSchema)
 		if err != nil {
-			return fmt.Errorf("error generating schema: %w", err)
-		}
-
-		a.responseFormat = &ResponseFormat{
-			Type: "json_schema",
-			JSONSchema: &JSONSchema{
-				Name:   schemaName,
-				Schema: schema,
-				Strict: true,
-			},
+			return err
 		}
+		a.responseFormat = format
 		return nil
 	}
 }
@@ -253,8 +355,16 @@ func WithJSONResponseFormat(schemaName string, structSchema any) AgentOption {
 func NewAgent(options ...AgentOption) (Agent, error) {
 	a := &agent{
 		tools:       make(map[string]Tool),
+		sessions:    make(map[string]*session),
 		temperature: 1.0,              // Default temperature
 		timeout:     30 * time.Second, // Default timeout
+		tracer:      noopTracer{},
+		logger:      discardLogger(),
+		strategy:    nativeStrategy{},
+		atomicTurns: true,
+
+		guardrailRetries:  defaultGuardrailRetries,
+		validateArguments: true,
 	}
 
 	for _, option := range options {
@@ -270,7 +380,7 @@ func NewAgent(options ...AgentOption) (Agent, error) {
 	if a.name == "" {
 		return nil, errors.New("name is required")
 	}
-	if a.memory == nil {
+	if a.memory == nil && a.memoryFactory == nil {
 		return nil, errors.New("memory is required")
 	}
 	if a.model == "" {
@@ -285,8 +395,34 @@ func (a *agent) GetName() string {
 	return a.name
 }
 
+// GetDescription returns the agent's description.
+func (a *agent) GetDescription() string {
+	return a.description
+}
+
 // Chat processes a chat request with the provided options.
 func (a *agent) Chat(ctx context.Context, options ...ChatOption) (string, error) {
+	result, err := a.ChatDetailed(ctx, options...)
+	if err != nil {
+		return "", err
+	}
+	return result.Content, nil
+}
+
+// ChatDetailed processes a chat request and returns everything that happened during the turn.
+func (a *agent) ChatDetailed(ctx context.Context, options ...ChatOption) (*ChatResult, error) {
+	return a.track(ctx, options, a.chat)
+}
+
+// track runs a chat turn inside its span, reporting its outcome to the logs, the OnError hooks and the event handlers.
+func (a *agent) track(ctx context.Context, options []ChatOption, run func(context.Context, *chatRequest, *eventEmitter) (*ChatResult, error)) (*ChatResult, error) {
+	a.mutex.RLock()
+	model := a.model
+	a.mutex.RUnlock()
+
+	ctx, span := a.tracer.Start(ctx, SpanChat, Attr(AttrAgentName, a.name), Attr(AttrModel, model))
+	defer span.End()
+
 	// Apply default values
 	req := &chatRequest{}
 
@@ -295,15 +431,58 @@ func (a *agent) Chat(ctx context.Context, options ...ChatOption) (string, error)
 		opt(req)
 	}
 
+	events := newEventEmitter(a.name, a.eventHandlers, req.eventHandlers)
+	events.emit(ctx, Event{Type: EventTurnStarted})
+
+	start := time.Now()
+	result, err := run(ctx, req, events)
+	if err != nil {
+		span.RecordError(err)
+		a.hooks.onError(ctx, err)
+		events.emit(ctx, Event{Type: EventError, Err: err})
+		a.logger.ErrorContext(ctx, "chat failed",
+			slog.String("agent", a.name),
+			slog.Duration("latency", time.Since(start)),
+			slog.Any("error", err),
+		)
+		return nil, err
+	}
+	result.Duration = time.Since(start)
+	span.SetAttributes(usageAttributes(result.Usage)...)
+	a.logger.InfoContext(ctx, "chat completed",
+		append([]any{
+			slog.String("agent", a.name),
+			slog.Duration("latency", result.Duration),
+			slog.Int("rounds", result.Rounds),
+			contentAttr("response", result.Content, a.debugLogging),
+		}, usageLogAttrs(result.Usage)...)...,
+	)
+	events.emit(ctx, Event{Type: EventTurnFinished, Result: result})
+	return result, nil
+}
+
+// chat implements ChatDetailed; errors are reported to the OnError hooks and the event handlers by the caller.
+func (a *agent) chat(ctx context.Context, req *chatRequest, events *eventEmitter) (*ChatResult, error) {
 	// Validate required fields
 	if req.userName == "" {
-		return "", errors.New("user name is required")
+		return nil, errors.New("user name is required")
 	}
 	if req.input == "" {
-		return "", errors.New("input is required")
+		return nil, errors.New("input is required")
 	}
+	extraTools, err := extraToolSet(req.extraTools)
+	if err != nil {
+		return nil, err
+	}
+
+	var guardrailUsage Usage
+	input, trips, _, err := runGuardrails(ctx, GuardrailStageInput, joinGuardrails(a.inputGuardrails, req.inputGuardrails), req.input, &guardrailUsage)
+	if err != nil {
+		return nil, err
+	}
+	// From here on the turn sees only the guarded input: the system prompt, the checkpoint and the logs.
+	req.input = input
 
-	a.mutex.Lock()
 	// Add the user's message to memory
 	message := Message{
 		Role:    RoleUser,
@@ -316,147 +495,523 @@ func (a *agent) Chat(ctx context.Context, options ...ChatOption) (string, error)
 		message.ImageURLs = req.imageURLs
 	}
 
-	a.memory.Add(message)
+	a.logger.DebugContext(ctx, "chat started",
+		slog.String("agent", a.name),
+		slog.String("user", req.userName),
+		contentAttr("input", req.input, a.debugLogging),
+		slog.Int("images", len(req.imageURLs)),
+	)
+
+	t := &turn{
+		input:            input,
+		dryRun:           req.dryRun,
+		extraTools:       extraTools,
+		outputGuardrails: joinGuardrails(a.outputGuardrails, req.outputGuardrails),
+		events:           events,
+		result:           &ChatResult{AgentName: a.name, GuardrailTrips: trips, Usage: guardrailUsage},
+	}
+	return a.execute(ctx, t, req, nil, func(ctx context.Context) error {
+		return a.runTurn(ctx, t, req, message)
+	})
+}
+
+// execute runs a turn in its conversation session: it renders the system prompt, opens the turn's
+// memory (a transaction for atomic turns), sets up checkpointing from cp (a new checkpoint when nil)
+// and calls run, then commits or rolls back the memory and records the outcome in the checkpoint.
+func (a *agent) execute(ctx context.Context, t *turn, req *chatRequest, cp *Checkpoint, run func(context.Context) error) (*ChatResult, error) {
+	systemPrompt, err := a.renderSystemPrompt(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	t.systemPrompt = systemPrompt
+	if t.examples, err = a.selectExamples(ctx, t.input); err != nil {
+		return nil, err
+	}
+
+	sess, err := a.session(req.sessionID)
+	if err != nil {
+		return nil, err
+	}
+	// Serialize turns within the session so their messages never interleave.
+	sess.mutex.Lock()
+	defer sess.mutex.Unlock()
+
+	memory := sess.memory
+	var tx MemoryTx
+	switch {
+	case req.ephemeral:
+		memory = newOverlayMemory(memory)
+		t.ephemeral = true
+	case a.atomicTurns:
+		if tx, err = beginTx(ctx, memory); err != nil {
+			return nil, err
+		}
+		memory = tx
+		t.atomic = true
+	}
+	t.memory = memory
+
+	if !req.ephemeral {
+		t.checkpoint = a.newCheckpointer(req, cp)
+		if t.checkpoint != nil {
+			t.result.RunID = t.checkpoint.cp.RunID
+		}
+	}
 
+	err = run(ctx)
+	if tx != nil {
+		if err = a.finishTx(ctx, tx, err); err == nil {
+			for i := range t.uncommitted {
+				t.events.emit(ctx, Event{Type: EventMemoryUpdated, Message: &t.uncommitted[i]})
+			}
+		}
+	}
+	if err != nil {
+		if errors.Is(err, errDryRun) {
+			return t.result, nil
+		}
+		a.checkpointFailure(ctx, t, err)
+		return nil, err
+	}
+	if err := a.checkpoint(ctx, t, CheckpointStageCompleted); err != nil {
+		return nil, err
+	}
+	return t.result, nil
+}
+
+// extraToolSet validates the tools passed with WithExtraTools and indexes them by name.
+func extraToolSet(tools []Tool) (map[string]Tool, error) {
+	extraTools := make(map[string]Tool, len(tools))
+	for _, tool := range tools {
+		name, err := validateTool(tool)
+		if err != nil {
+			return nil, fmt.Errorf("invalid extra tool: %w", err)
+		}
+		extraTools[name] = tool
+	}
+	return extraTools, nil
+}
+
+// runTurn stores the user's message and runs the agent's strategy within the turn timeout.
+func (a *agent) runTurn(ctx context.Context, t *turn, req *chatRequest, message Message) error {
+	if err := a.remember(ctx, t, message); err != nil {
+		return err
+	}
+	if err := a.checkpoint(ctx, t, CheckpointStageRunning); err != nil {
+		return err
+	}
+
+	a.mutex.RLock()
 	// Prepare messages: include the system prompt and the conversation memory
-	messages := a.prepareMessages()
+	messages := a.prepareMessages(t)
 	for _, additional := range req.additionalMessages {
 		messages = append(messages, additional...)
 	}
+	a.mutex.RUnlock()
 
 	// Prepare tool definitions to be used
-	tools := a.prepareTools()
-	a.mutex.Unlock()
+	tools, err := a.prepareTools(ctx, t, req)
+	if err != nil {
+		return err
+	}
+	t.tools = tools
+
+	ctx, cancel := context.WithTimeout(ctx, a.turnTimeout(req))
+	defer cancel()
 
+	return a.strategy.run(ctx, a, t, messages)
+}
+
+// turnTimeout returns the timeout of a chat request.
+func (a *agent) turnTimeout(req *chatRequest) time.Duration {
 	// Usar timeout específico si se proporciona, sino usar el del agente
-	timeout := a.timeout
 	if req.timeout != nil {
-		timeout = *req.timeout
+		return *req.timeout
 	}
+	return a.timeout
+}
 
-	return a.processWithTools(ctx, messages, tools, timeout)
+// remember runs the BeforeMemoryWrite hooks, stores the resulting message in the turn's memory
+// and records it in the turn result.
+func (a *agent) remember(ctx context.Context, t *turn, message Message) error {
+	stored, err := a.store(ctx, t, message)
+	if err != nil {
+		return err
+	}
+	t.result.Messages = append(t.result.Messages, stored)
+	return nil
 }
 
-// processWithTools handles the API request to OpenAI, including executing tool calls if required.
-// It manages context timeout, request setup, and response processing.
-func (a *agent) processWithTools(ctx context.Context, messages []Message, tools []ToolDefinition, timeout time.Duration) (string, error) {
-	ctx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
+// store runs the BeforeMemoryWrite hooks, stores the resulting message in the turn's memory,
+// reports it and returns it.
+func (a *agent) store(ctx context.Context, t *turn, message Message) (Message, error) {
+	if err := a.hooks.beforeMemoryWrite(ctx, &message); err != nil {
+		return Message{}, fmt.Errorf("memory write rejected: %w", err)
+	}
+	t.memory.Add(message)
+	switch {
+	case t.scratch || t.ephemeral:
+	case t.atomic:
+		t.uncommitted = append(t.uncommitted, message)
+	default:
+		t.events.emit(ctx, Event{Type: EventMemoryUpdated, Message: &message})
+	}
+	return message, nil
+}
+
+// processWithTools handles the API requests to the LLM, executing tool calls until the model produces a final answer.
+// Each iteration of the loop is one round; the final answer is stored in memory and in the turn result.
+func (a *agent) processWithTools(ctx context.Context, t *turn, messages []Message) error {
+	for {
+		choice, err := a.runRound(ctx, t, messages)
+		if err != nil {
+			return err
+		}
 
+		// If the response indicates that tool calls are required, execute them and start a new round.
+		if choice.FinishReason == FinishReasonToolCalls {
+			if err := a.handleToolCalls(ctx, t, choice.Message.ToolCalls); err != nil {
+				return err
+			}
+			// A handoff ends the turn; the target agent produces the answer.
+			if t.result.Handoff != nil {
+				return nil
+			}
+			a.mutex.RLock()
+			messages = a.prepareMessages(t)
+			a.mutex.RUnlock()
+			continue
+		}
+
+		response := choice.Message.Content
+		if choice.FinishReason == FinishReasonLength && a.continueOnLength {
+			response, err = a.continueTruncated(ctx, t, messages, response)
+			if err != nil {
+				return err
+			}
+		}
+
+		return a.answer(ctx, t, response)
+	}
+}
+
+// answer completes the turn with the model's answer: it runs the reflection loop and the output
+// guardrails when configured, then stores the final answer in memory and in the turn result.
+func (a *agent) answer(ctx context.Context, t *turn, content string) error {
+	// Scratch turns produce intermediate results; only the answer to the user is reflected on.
+	if a.reflection != nil && !t.scratch {
+		var err error
+		content, err = a.reflect(ctx, t, content)
+		if err != nil {
+			return err
+		}
+	}
+	if len(t.outputGuardrails) > 0 {
+		var err error
+		content, err = a.guardOutput(ctx, t, content)
+		if err != nil {
+			return err
+		}
+	}
+
+	t.result.Content = content
+	return a.remember(ctx, t, Message{
+		Role:    RoleAssistant,
+		Content: content,
+		Name:    a.name,
+	})
+}
+
+// runRound sends one request built from messages and returns the first choice of the response.
+func (a *agent) runRound(ctx context.Context, t *turn, messages []Message) (Choice, error) {
+	a.mutex.RLock()
 	req := ChatCompletionRequest{
 		Model:          a.model,
 		Messages:       messages,
-		Tools:          tools,
+		Tools:          t.tools,
 		Temperature:    a.temperature,
 		ResponseFormat: a.responseFormat,
 	}
+	a.mutex.RUnlock()
+
+	return a.complete(ctx, t, req)
+}
 
-	resp, err := a.client.CreateChatCompletion(ctx, req)
+// complete sends req as a new round of the turn and returns the first choice of the response.
+// It records the round, its usage and its finish reason in the turn result. The response text is
+// reported as content deltas, except in scratch turns, whose output is not the answer.
+func (a *agent) complete(ctx context.Context, t *turn, req ChatCompletionRequest) (Choice, error) {
+	return a.completeWith(ctx, t, a.client, req, !t.scratch)
+}
+
+// completeWith is complete sending req to client. deltas tells whether the response text is
+// reported as EventContentDelta, which only requests producing answer text should do.
+func (a *agent) completeWith(ctx context.Context, t *turn, client LLMClient, req ChatCompletionRequest, deltas bool) (Choice, error) {
+	if err := a.hooks.beforeRequest(ctx, &req); err != nil {
+		return Choice{}, fmt.Errorf("request rejected: %w", err)
+	}
+	if t.dryRun {
+		t.result.Request = &req
+		return Choice{}, errDryRun
+	}
+	t.result.Rounds++
+	t.events.emit(ctx, Event{Type: EventRequestSent, Round: t.result.Rounds, Request: &req})
+
+	resp, err := a.callLLM(ctx, t, client, req, deltas)
 	if err != nil {
-		log.Printf("error in chat completion: %v", err)
-		return "", fmt.Errorf("error in chat completion: %w", err)
+		return Choice{}, err
 	}
+	t.mutex.Lock()
+	t.result.Usage = t.result.Usage.Add(resp.Usage)
+	t.mutex.Unlock()
 
 	if len(resp.Choices) == 0 {
-		return "", errors.New("no response choices available")
+		return Choice{}, errors.New("no response choices available")
 	}
 
 	choice := resp.Choices[0]
+	t.result.FinishReason = choice.FinishReason
+	return choice, nil
+}
 
-	// If the response indicates that tool calls are required, execute them.
-	if choice.FinishReason == FinishReasonToolCalls {
-		if err := a.handleToolCalls(choice.Message.ToolCalls); err != nil {
-			return "", err
-		}
-		a.mutex.Lock()
-		newMessages := a.prepareMessages()
-		a.mutex.Unlock()
-		return a.processWithTools(ctx, newMessages, tools, timeout)
+// callLLM sends a single request to the LLM inside its own span and runs the AfterResponse hooks.
+func (a *agent) callLLM(ctx context.Context, t *turn, client LLMClient, req ChatCompletionRequest, deltas bool) (ChatCompletionResponse, error) {
+	round := t.result.Rounds
+	ctx, span := a.tracer.Start(ctx, SpanLLMCall,
+		Attr(AttrAgentName, a.name),
+		Attr(AttrModel, req.Model),
+		Attr(AttrRound, round),
+	)
+	defer span.End()
+
+	logAttrs := []any{
+		slog.String("agent", a.name),
+		slog.String("model", req.Model),
+		slog.Int("round", round),
+	}
+	if a.debugLogging {
+		logAttrs = append(logAttrs, slog.Any("messages", req.Messages))
 	}
 
-	// Store the assistant's response in memory and return it.
-	response := choice.Message.Content
-	a.mutex.Lock()
-	a.memory.Add(Message{
-		Role:    RoleAssistant,
-		Content: response,
-		Name:    a.name,
-	})
-	a.mutex.Unlock()
+	start := time.Now()
+	resp, err := a.sendRequest(ctx, t, client, req, round, deltas)
+	latency := time.Since(start)
+	if err != nil {
+		a.logger.ErrorContext(ctx, "chat completion failed",
+			append(logAttrs, slog.Duration("latency", latency), slog.Any("error", err))...,
+		)
+		err = fmt.Errorf("error in chat completion: %w", err)
+		span.RecordError(err)
+		return resp, err
+	}
+
+	span.SetAttributes(usageAttributes(resp.Usage)...)
+	logAttrs = append(logAttrs, slog.Duration("latency", latency))
+	logAttrs = append(logAttrs, usageLogAttrs(resp.Usage)...)
+	if len(resp.Choices) > 0 {
+		span.SetAttributes(Attr(AttrFinishReason, resp.Choices[0].FinishReason))
+		logAttrs = append(logAttrs,
+			slog.String("finish_reason", resp.Choices[0].FinishReason),
+			slog.Int("tool_calls", len(resp.Choices[0].Message.ToolCalls)),
+			contentAttr("response", resp.Choices[0].Message.Content, a.debugLogging),
+		)
+	}
+	a.logger.DebugContext(ctx, "chat completion", logAttrs...)
 
-	return response, nil
+	if err := a.hooks.afterResponse(ctx, &resp); err != nil {
+		err = fmt.Errorf("response rejected: %w", err)
+		span.RecordError(err)
+		return resp, err
+	}
+	return resp, nil
+}
+
+// sendRequest sends req to client. When the turn has event listeners, the answer is streamed
+// if the client supports it, and reported as a single content delta otherwise.
+func (a *agent) sendRequest(ctx context.Context, t *turn, client LLMClient, req ChatCompletionRequest, round int, deltas bool) (ChatCompletionResponse, error) {
+	if t.events == nil || !deltas {
+		return client.CreateChatCompletion(ctx, req)
+	}
+	if streaming, ok := client.(StreamingLLMClient); ok {
+		return streaming.CreateChatCompletionStream(ctx, req, func(delta string) {
+			t.events.emit(ctx, Event{Type: EventContentDelta, Round: round, Delta: delta})
+		})
+	}
+
+	resp, err := client.CreateChatCompletion(ctx, req)
+	if err == nil && len(resp.Choices) > 0 && resp.Choices[0].Message.Content != "" {
+		t.events.emit(ctx, Event{Type: EventContentDelta, Round: round, Delta: resp.Choices[0].Message.Content})
+	}
+	return resp, err
 }
 
 // handleToolCalls executes each tool call concurrently and collects their results.
-// It updates the agent's memory with the tool results and handles errors during execution.
-func (a *agent) handleToolCalls(toolCalls []ToolCall) error {
-	a.mutex.Lock()
-	a.memory.Add(Message{
+// It updates the turn's memory and result with the tool results and handles errors during execution.
+func (a *agent) handleToolCalls(ctx context.Context, t *turn, toolCalls []ToolCall) error {
+	if err := a.remember(ctx, t, Message{
 		Role:      RoleAssistant,
 		ToolCalls: toolCalls,
 		Content:   "Executing tool calls...",
 		Name:      a.name,
-	})
-	a.mutex.Unlock()
+	}); err != nil {
+		return err
+	}
+	if err := a.checkpointToolCalls(ctx, t, toolCalls); err != nil {
+		return err
+	}
+	return a.runToolCalls(ctx, t, toolCalls, nil)
+}
 
-	var wg sync.WaitGroup
-	type toolResult struct {
-		CallID  string
-		Name    string
-		Content string
-		Error   error
+// runToolCalls executes the tool calls requested in one round concurrently, reusing the results of
+// calls already completed (when resuming a checkpoint), and stores the results in order.
+func (a *agent) runToolCalls(ctx context.Context, t *turn, toolCalls []ToolCall, completed []ToolCallResult) error {
+	done := make(map[string]ToolCallResult, len(completed))
+	for _, r := range completed {
+		done[r.Call.ID] = r
 	}
 
-	results := make([]toolResult, len(toolCalls))
+	var wg sync.WaitGroup
+	results := make([]ToolCallResult, len(toolCalls))
+	errs := make([]error, len(toolCalls))
 
+	ctx = a.toolContext(ctx, t)
 	for i, call := range toolCalls {
+		if r, ok := done[call.ID]; ok && call.ID != "" {
+			results[i] = r
+			continue
+		}
 		wg.Add(1)
 		go func(i int, call ToolCall) {
 			defer wg.Done()
-			results[i].CallID = call.ID
-			results[i].Name = call.Name
-
-			a.mutex.RLock()
-			tool, exists := a.tools[call.Name]
-			a.mutex.RUnlock()
-
-			if !exists {
-				results[i].Error = fmt.Errorf("tool %s not found", call.Name)
-				return
-			}
-
-			result, err := tool.Execute(call.Args)
-			if err != nil {
-				results[i].Error = fmt.Errorf("error executing tool %s: %w", call.Name, err)
-				return
+			results[i], errs[i] = a.runToolCall(ctx, t, call)
+			if errs[i] == nil {
+				errs[i] = a.checkpointToolCall(ctx, t, results[i])
 			}
-
-			resultBytes, err := json.Marshal(result)
-			if err != nil {
-				results[i].Error = fmt.Errorf("error marshalling tool result: %w", err)
-				return
-			}
-
-			results[i].Content = string(resultBytes)
 		}(i, call)
 	}
 
 	wg.Wait()
 
-	for _, r := range results {
-		if r.Error != nil {
-			return r.Error
+	for i, r := range results {
+		if errs[i] != nil {
+			return errs[i]
 		}
-		a.mutex.Lock()
-		a.memory.Add(Message{
+		t.result.ToolCalls = append(t.result.ToolCalls, r)
+		if err := a.remember(ctx, t, Message{
 			Role:       RoleTool,
-			Content:    r.Content,
-			Name:       r.Name,
-			ToolCallID: r.CallID,
-		})
-		a.mutex.Unlock()
+			Content:    r.Result,
+			Name:       r.Call.Name,
+			ToolCallID: r.Call.ID,
+		}); err != nil {
+			return err
+		}
 	}
-	return nil
+	return a.checkpoint(ctx, t, CheckpointStageRunning)
+}
+
+// toolContext exposes the turn to the tools executed from the returned context.
+func (a *agent) toolContext(ctx context.Context, t *turn) context.Context {
+	return context.WithValue(ctx, toolCallContextKey{}, &toolCallContext{agentName: a.name, turn: t})
+}
+
+// runToolCall executes a single tool call inside its own span, running the tool hooks around it.
+func (a *agent) runToolCall(ctx context.Context, t *turn, call ToolCall) (result ToolCallResult, err error) {
+	ctx, span := a.tracer.Start(ctx, SpanToolCall,
+		Attr(AttrAgentName, a.name),
+		Attr(AttrToolName, call.Name),
+		Attr(AttrToolCallID, call.ID),
+	)
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+		}
+		span.End()
+	}()
+
+	start := time.Now()
+	if err := a.hooks.beforeToolCall(ctx, &call); err != nil {
+		if errors.Is(err, ErrSkipToolCall) {
+			a.logger.WarnContext(ctx, "tool call skipped",
+				slog.String("agent", a.name),
+				slog.String("tool", call.Name),
+				slog.Any("reason", err),
+			)
+			content, _ := json.Marshal(map[string]string{"error": err.Error()})
+			result := ToolCallResult{Call: call, Result: string(content), Skipped: true, Duration: time.Since(start)}
+			t.events.emit(ctx, Event{Type: EventToolCallFinished, ToolCall: &call, Tool: &result})
+			return result, nil
+		}
+		return ToolCallResult{Call: call}, fmt.Errorf("tool call %s rejected: %w", call.Name, err)
+	}
+	t.events.emit(ctx, Event{Type: EventToolCallStarted, ToolCall: &call})
+
+	content, err := a.executeTool(ctx, t, call)
+	err = a.hooks.afterToolCall(ctx, call, &content, err)
+	result = ToolCallResult{Call: call, Result: content, Duration: time.Since(start)}
+	a.logToolCall(ctx, call, content, err, result.Duration)
+	t.events.emit(ctx, Event{Type: EventToolCallFinished, ToolCall: &call, Tool: &result, Err: err})
+	return result, err
+}
+
+// logToolCall records the outcome of a tool execution.
+func (a *agent) logToolCall(ctx context.Context, call ToolCall, result string, err error, latency time.Duration) {
+	attrs := []any{
+		slog.String("agent", a.name),
+		slog.String("tool", call.Name),
+		slog.String("call_id", call.ID),
+		slog.Duration("latency", latency),
+		contentAttr("args", string(call.Args), a.debugLogging),
+	}
+	if err != nil {
+		a.logger.ErrorContext(ctx, "tool call failed", append(attrs, slog.Any("error", err))...)
+		return
+	}
+	a.logger.DebugContext(ctx, "tool call completed", append(attrs, contentAttr("result", result, a.debugLogging))...)
+}
+
+// executeTool runs a single tool call and returns its JSON-encoded result.
+// Tools implementing ContextTool receive the turn's context, and a Handoff result is recorded in the turn.
+func (a *agent) executeTool(ctx context.Context, t *turn, call ToolCall) (string, error) {
+	tool, exists := t.extraTools[call.Name]
+	if !exists {
+		a.mutex.RLock()
+		tool, exists = a.tools[call.Name]
+		a.mutex.RUnlock()
+	}
+
+	if !exists || !t.offersTool(call.Name) {
+		return "", fmt.Errorf("tool %s not found", call.Name)
+	}
+
+	var result interface{}
+	var err error
+	args, invalid := a.repairCallArguments(ctx, tool, call)
+	if invalid == nil {
+		call.Args = args
+		invalid = a.checkToolArguments(ctx, tool, call)
+	}
+	if invalid != nil {
+		result = *invalid
+	} else if contextTool, ok := tool.(ContextTool); ok {
+		result, err = contextTool.ExecuteContext(ctx, call.Args)
+	} else {
+		result, err = tool.Execute(call.Args)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error executing tool %s: %w", call.Name, err)
+	}
+	if handoff, ok := result.(Handoff); ok {
+		handoff.From = a.name
+		t.recordHandoff(handoff)
+		result = handoff
+	}
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling tool result: %w", err)
+	}
+
+	return string(resultBytes), nil
 }
 
 // validateAndFixMessageSequence ensures tool_calls and tool messages are properly paired
@@ -500,29 +1055,95 @@ func validateAndFixMessageSequence(messages []Message) []Message {
 	return validMessages
 }
 
-// prepareMessages compiles the messages to be sent to the API, including the system prompt and conversation memory.
-func (a *agent) prepareMessages() []Message {
+// prepareMessages compiles the messages to be sent to the API: the system prompt, the turn's
+// few-shot examples and the conversation memory.
+// The caller must hold at least a read lock on the agent's mutex.
+func (a *agent) prepareMessages(t *turn) []Message {
 	var msgs []Message
-	if a.systemPrompt != "" {
+	if t.systemPrompt != "" {
 		msgs = append(msgs, Message{
-			Role:    getSystemRole(a.model),
-			Content: a.systemPrompt,
+			Role:            getSystemRole(a.model),
+			Content:         t.systemPrompt,
+			CacheBreakpoint: a.cacheBreakpoints,
 		})
 	}
+	if len(t.examples) > 0 {
+		msgs = append(msgs, t.examples...)
+		msgs[len(msgs)-1].CacheBreakpoint = a.cacheBreakpoints
+	}
 
 	// Get memory messages and validate tool call sequences
-	memoryMessages := a.memory.Get()
+	memoryMessages := t.memory.Get()
 	validatedMessages := validateAndFixMessageSequence(memoryMessages)
+	if a.cacheBreakpoints && len(validatedMessages) > 0 {
+		validatedMessages[len(validatedMessages)-1].CacheBreakpoint = true
+	}
 
 	msgs = append(msgs, validatedMessages...)
 	return msgs
 }
 
-// prepareTools compiles the list of tools to be included in the API request.
-func (a *agent) prepareTools() []ToolDefinition {
+// prepareTools compiles the list of tools to be included in the API request: the agent's tools
+// allowed by the chat request and chosen by the tool selector, if any, plus the extra tools. The
+// list is sorted by name so that identical turns produce identical requests and providers can
+// cache their prefix.
+func (a *agent) prepareTools(ctx context.Context, t *turn, req *chatRequest) ([]ToolDefinition, error) {
 	var defs []ToolDefinition
-	for _, tool := range a.tools {
+	a.mutex.RLock()
+	for name, tool := range a.tools {
+		if _, replaced := t.extraTools[name]; !replaced && toolAllowed(name, req) {
+			defs = append(defs, tool.GetDefinition())
+		}
+	}
+	selector := a.toolSelector
+	a.mutex.RUnlock()
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+
+	if selector != nil && len(defs) > 0 {
+		selected, err := selector.SelectTools(ctx, t.input, defs)
+		if err != nil {
+			return nil, fmt.Errorf("tool selection failed: %w", err)
+		}
+		defs = selected
+	}
+	for _, tool := range t.extraTools {
 		defs = append(defs, tool.GetDefinition())
 	}
-	return defs
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs, nil
+}
+
+// completeAnswer requests a new final answer for messages, without tools.
+func (a *agent) completeAnswer(ctx context.Context, t *turn, messages []Message) (string, error) {
+	a.mutex.RLock()
+	req := ChatCompletionRequest{
+		Model:          a.model,
+		Messages:       messages,
+		Temperature:    a.temperature,
+		ResponseFormat: a.responseFormat,
+	}
+	a.mutex.RUnlock()
+
+	choice, err := a.complete(ctx, t, req)
+	if err != nil {
+		return "", err
+	}
+	return choice.Message.Content, nil
+}
+
+// redraft asks for a new answer after a rejected draft, with feedback on why it was rejected.
+// Neither the draft nor the feedback is stored in memory.
+func (a *agent) redraft(ctx context.Context, t *turn, draft, feedback string) (string, error) {
+	a.mutex.RLock()
+	messages := append(a.prepareMessages(t),
+		Message{Role: RoleAssistant, Content: draft, Name: a.name},
+		Message{Role: RoleUser, Content: feedback},
+	)
+	a.mutex.RUnlock()
+
+	content, err := a.completeAnswer(ctx, t, messages)
+	if err != nil {
+		return "", fmt.Errorf("error redrafting answer: %w", err)
+	}
+	return content, nil
 }
@@ -0,0 +1,148 @@
+package syndicate
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// ConfigurableAgent is an Agent whose configuration can be inspected and changed at runtime.
+// Agents created with NewAgent implement it:
+//
+//	configurable, ok := agent.(syndicate.ConfigurableAgent)
+//
+// All methods are safe for concurrent use. Changes apply to chat rounds started afterwards;
+// memory and sessions are left untouched.
+type ConfigurableAgent interface {
+	Agent
+	// AddTool registers a tool, replacing any tool with the same name.
+	AddTool(tool Tool) error
+	// RemoveTool unregisters the named tool and reports whether it was present.
+	RemoveTool(name string) bool
+	// SetSystemPrompt replaces the static system prompt. A system prompt provider, if configured, still takes precedence.
+	SetSystemPrompt(prompt string)
+	// SetModel switches the model used for new requests.
+	SetModel(model string) error
+	// SetTemperature changes the sampling temperature.
+	SetTemperature(temperature float32) error
+	// SetResponseFormat sets the response format; nil restores plain text responses.
+	SetResponseFormat(format *ResponseFormat)
+	// SetJSONResponseFormat configures a JSON schema response format generated from a struct.
+	SetJSONResponseFormat(schemaName string, structSchema any) error
+	// Tools returns the definitions of the registered tools, sorted by name.
+	Tools() []ToolDefinition
+	// Config returns a snapshot of the current configuration.
+	Config() AgentConfig
+}
+
+// AgentConfig is a snapshot of an agent's configuration.
+type AgentConfig struct {
+	Name           string
+	Description    string
+	SystemPrompt   string
+	Model          string
+	Temperature    float32
+	Timeout        time.Duration
+	ResponseFormat *ResponseFormat
+	ToolNames      []string // Sorted by name.
+}
+
+// AddTool registers a tool, replacing any tool with the same name.
+func (a *agent) AddTool(tool Tool) error {
+	name, err := validateTool(tool)
+	if err != nil {
+		return err
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.tools[name] = tool
+	return nil
+}
+
+// RemoveTool unregisters the named tool and reports whether it was present.
+func (a *agent) RemoveTool(name string) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	_, exists := a.tools[name]
+	delete(a.tools, name)
+	return exists
+}
+
+// SetSystemPrompt replaces the system prompt.
+func (a *agent) SetSystemPrompt(prompt string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.systemPrompt = prompt
+}
+
+// SetModel switches the model used for new requests.
+func (a *agent) SetModel(model string) error {
+	if model == "" {
+		return errors.New("model cannot be empty")
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.model = model
+	return nil
+}
+
+// SetTemperature changes the sampling temperature.
+func (a *agent) SetTemperature(temperature float32) error {
+	if temperature < 0 || temperature > 2 {
+		return errors.New("temperature must be between 0 and 2")
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.temperature = temperature
+	return nil
+}
+
+// SetResponseFormat sets the response format; nil restores plain text responses.
+func (a *agent) SetResponseFormat(format *ResponseFormat) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.responseFormat = format
+}
+
+// SetJSONResponseFormat configures a JSON schema response format generated from a struct.
+func (a *agent) SetJSONResponseFormat(schemaName string, structSchema any) error {
+	format, err := newJSONResponseFormat(schemaName, structSchema)
+	if err != nil {
+		return err
+	}
+	a.SetResponseFormat(format)
+	return nil
+}
+
+// Tools returns the definitions of the registered tools, sorted by name.
+func (a *agent) Tools() []ToolDefinition {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	defs := make([]ToolDefinition, 0, len(a.tools))
+	for _, tool := range a.tools {
+		defs = append(defs, tool.GetDefinition())
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// Config returns a snapshot of the current configuration.
+func (a *agent) Config() AgentConfig {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	names := make([]string, 0, len(a.tools))
+	for name := range a.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return AgentConfig{
+		Name:           a.name,
+		Description:    a.description,
+		SystemPrompt:   a.systemPrompt,
+		Model:          a.model,
+		Temperature:    a.temperature,
+		Timeout:        a.timeout,
+		ResponseFormat: a.responseFormat,
+		ToolNames:      names,
+	}
+}
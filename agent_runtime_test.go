@@ -0,0 +1,141 @@
+package syndicate
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestConfigurableAgentMutations verifica que los cambios en tiempo de ejecución se apliquen a los siguientes requests.
+func TestConfigurableAgentMutations(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("uno"), textResponse("dos")}}
+	mem := NewSimpleMemory()
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("mutable"),
+		WithDescription("agente mutable"),
+		WithMemory(mem),
+		WithModel("gpt-4o"),
+		WithSystemPrompt("prompt inicial"),
+		WithTool(&fakeTool{def: ToolDefinition{Name: "b"}}),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	configurable, ok := agent.(ConfigurableAgent)
+	if !ok {
+		t.Fatal("el agente debería implementar ConfigurableAgent")
+	}
+
+	ctx := context.Background()
+	if _, err := agent.Chat(ctx, WithUserName("user"), WithInput("hola")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	if err := configurable.AddTool(&fakeTool{def: ToolDefinition{Name: "a"}}); err != nil {
+		t.Fatalf("error agregando herramienta: %v", err)
+	}
+	if !configurable.RemoveTool("b") || configurable.RemoveTool("b") {
+		t.Error("RemoveTool debería reportar si la herramienta existía")
+	}
+	configurable.SetSystemPrompt("prompt nuevo")
+	if err := configurable.SetModel("gpt-4o-mini"); err != nil {
+		t.Fatalf("error cambiando modelo: %v", err)
+	}
+	if err := configurable.SetTemperature(0.2); err != nil {
+		t.Fatalf("error cambiando temperatura: %v", err)
+	}
+	if err := configurable.SetJSONResponseFormat("answer", struct {
+		Text string `json:"text"`
+	}{}); err != nil {
+		t.Fatalf("error cambiando formato: %v", err)
+	}
+
+	if _, err := agent.Chat(ctx, WithUserName("user"), WithInput("otra vez")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	req := client.Requests()[1]
+	if req.Model != "gpt-4o-mini" || req.Temperature != 0.2 {
+		t.Errorf("modelo o temperatura no actualizados: %s %v", req.Model, req.Temperature)
+	}
+	if req.Messages[0].Content != "prompt nuevo" {
+		t.Errorf("system prompt no actualizado: %q", req.Messages[0].Content)
+	}
+	if len(req.Tools) != 1 || req.Tools[0].Name != "a" {
+		t.Errorf("herramientas no actualizadas: %+v", req.Tools)
+	}
+	if req.ResponseFormat == nil || req.ResponseFormat.JSONSchema.Name != "answer" {
+		t.Errorf("formato de respuesta no actualizado: %+v", req.ResponseFormat)
+	}
+	if len(mem.Get()) != 4 {
+		t.Errorf("la memoria debería conservarse tras los cambios, tiene %d mensajes", len(mem.Get()))
+	}
+
+	config := configurable.Config()
+	if config.Name != "mutable" || config.Description != "agente mutable" || config.Model != "gpt-4o-mini" ||
+		strings.Join(config.ToolNames, ",") != "a" {
+		t.Errorf("configuración inesperada: %+v", config)
+	}
+	if tools := configurable.Tools(); len(tools) != 1 || tools[0].Name != "a" {
+		t.Errorf("Tools inesperado: %+v", tools)
+	}
+}
+
+// TestConfigurableAgentValidation verifica las validaciones de los métodos de mutación.
+func TestConfigurableAgentValidation(t *testing.T) {
+	agent, _ := NewAgent(
+		WithClient(&fakeLLMClient{}),
+		WithName("mutable"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+	)
+	configurable := agent.(ConfigurableAgent)
+
+	if err := configurable.AddTool(nil); err == nil {
+		t.Error("se esperaba error por herramienta nil")
+	}
+	if err := configurable.SetModel(""); err == nil {
+		t.Error("se esperaba error por modelo vacío")
+	}
+	if err := configurable.SetTemperature(3); err == nil {
+		t.Error("se esperaba error por temperatura fuera de rango")
+	}
+	if err := configurable.SetJSONResponseFormat("", struct{}{}); err == nil {
+		t.Error("se esperaba error por nombre de esquema vacío")
+	}
+	configurable.SetResponseFormat(nil)
+	if configurable.Config().ResponseFormat != nil {
+		t.Error("SetResponseFormat(nil) debería quitar el formato")
+	}
+}
+
+// TestConfigurableAgentConcurrentMutation verifica que mutar el agente durante chats concurrentes sea seguro.
+func TestConfigurableAgentConcurrentMutation(t *testing.T) {
+	agent, _ := NewAgent(
+		WithClient(echoLLMClient{}),
+		WithName("mutable"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+	)
+	configurable := agent.(ConfigurableAgent)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			agent.Chat(context.Background(), WithUserName("user"), WithInput("hola"))
+		}()
+		go func() {
+			defer wg.Done()
+			configurable.SetSystemPrompt("cambiado")
+			configurable.AddTool(&fakeTool{def: ToolDefinition{Name: "t"}})
+			configurable.RemoveTool("t")
+			configurable.SetModel("gpt-4o-mini")
+		}()
+	}
+	wg.Wait()
+}
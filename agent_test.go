@@ -678,3 +678,59 @@ func TestAgentWithJSONResponseFormat(t *testing.T) {
 		t.Errorf("se esperaba respuesta JSON, se obtuvo '%s'", result)
 	}
 }
+
+// TestDeterministicRequests verifica que las herramientas se envíen ordenadas por nombre y que
+// WithCacheBreakpoints marque el prompt de sistema y el último mensaje de la conversación.
+func TestDeterministicRequests(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("uno"), textResponse("dos")}}
+	memory := NewSimpleMemory()
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("Agente"),
+		WithSystemPrompt("Eres un asistente."),
+		WithMemory(memory),
+		WithModel(openai.GPT4),
+		WithTools(
+			&fakeTool{def: ToolDefinition{Name: "zeta"}},
+			&fakeTool{def: ToolDefinition{Name: "alfa"}},
+			&fakeTool{def: ToolDefinition{Name: "mu"}},
+		),
+		WithCacheBreakpoints(),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	extra := &fakeTool{def: ToolDefinition{Name: "beta"}}
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("hola"), WithExtraTools(extra)); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("otra vez")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	requests := client.Requests()
+	var names []string
+	for _, tool := range requests[0].Tools {
+		names = append(names, tool.Name)
+	}
+	if strings.Join(names, ",") != "alfa,beta,mu,zeta" {
+		t.Errorf("orden de herramientas inesperado: %v", names)
+	}
+
+	messages := requests[1].Messages
+	var marked []int
+	for i, msg := range messages {
+		if msg.CacheBreakpoint {
+			marked = append(marked, i)
+		}
+	}
+	if len(marked) != 2 || marked[0] != 0 || marked[1] != len(messages)-1 {
+		t.Errorf("breakpoints inesperados en %v: %+v", marked, messages)
+	}
+	for _, msg := range memory.Get() {
+		if msg.CacheBreakpoint {
+			t.Errorf("la memoria no debe guardar breakpoints: %+v", msg)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// agentToolInput is the argument schema of tools created with NewAgentTool.
+type agentToolInput struct {
+	Task    string `json:"task" description:"The task the agent must carry out, stated as a self-contained request"`
+	Context string `json:"context" description:"Optional background information the agent needs, or an empty string"`
+}
+
+// AgentToolSummarizer turns the sub-agent's result into the value returned to the calling model.
+type AgentToolSummarizer func(ctx context.Context, result *ChatResult) (any, error)
+
+// agentToolConfig holds the configuration of an agent tool.
+type agentToolConfig struct {
+	name           string
+	description    string
+	userName       string
+	parentContext  bool
+	summarizer     AgentToolSummarizer
+	reportErrors   bool
+	propagateUsage bool
+}
+
+// AgentToolOption configures a tool created with NewAgentTool.
+type AgentToolOption func(*agentToolConfig) error
+
+// WithAgentToolName overrides the tool name, which defaults to the agent's name.
+func WithAgentToolName(name string) AgentToolOption {
+	return func(c *agentToolConfig) error {
+		if name == "" {
+			return errors.New("tool name cannot be empty")
+		}
+		c.name = name
+		return nil
+	}
+}
+
+// WithAgentToolDescription overrides the tool description, which defaults to the agent's description.
+func WithAgentToolDescription(description string) AgentToolOption {
+	return func(c *agentToolConfig) error {
+		if description == "" {
+			return errors.New("tool description cannot be empty")
+		}
+		c.description = description
+		return nil
+	}
+}
+
+// WithAgentToolUserName sets the user name the sub-agent sees for delegated tasks.
+// It defaults to the name of the calling agent.
+func WithAgentToolUserName(userName string) AgentToolOption {
+	return func(c *agentToolConfig) error {
+		if userName == "" {
+			return errors.New("user name cannot be empty")
+		}
+		c.userName = userName
+		return nil
+	}
+}
+
+// WithAgentToolParentContext appends the calling agent's conversation (user and assistant
+// messages) to the delegated task, so the sub-agent sees what led to the request.
+func WithAgentToolParentContext() AgentToolOption {
+	return func(c *agentToolConfig) error {
+		c.parentContext = true
+		return nil
+	}
+}
+
+// WithAgentToolSummarizer sets how the sub-agent's result is turned into the tool result.
+// By default only the final answer is returned.
+func WithAgentToolSummarizer(summarizer AgentToolSummarizer) AgentToolOption {
+	return func(c *agentToolConfig) error {
+		if summarizer == nil {
+			return errors.New("summarizer cannot be nil")
+		}
+		c.summarizer = summarizer
+		return nil
+	}
+}
+
+// WithAgentToolErrorReporting returns sub-agent failures to the calling model as the tool result
+// instead of aborting the caller's chat turn, so the model can retry or work around them.
+func WithAgentToolErrorReporting() AgentToolOption {
+	return func(c *agentToolConfig) error {
+		c.reportErrors = true
+		return nil
+	}
+}
+
+// WithAgentToolUsagePropagation sets whether the sub-agent's token usage is added to the
+// calling turn's usage. It is enabled by default.
+func WithAgentToolUsagePropagation(enabled bool) AgentToolOption {
+	return func(c *agentToolConfig) error {
+		c.propagateUsage = enabled
+		return nil
+	}
+}
+
+// agentTool implements ContextTool by delegating tasks to an Agent.
+type agentTool struct {
+	agent  Agent
+	config agentToolConfig
+	schema json.RawMessage
+}
+
+// invalidToolNameChars matches characters providers do not accept in function names.
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// NewAgentTool wraps an Agent as a Tool so that a manager agent can delegate tasks to it.
+// The tool takes a task and an optional context, runs a chat turn on the wrapped agent and
+// returns its answer.
+//
+// Example:
+//
+//	researchTool, err := syndicate.NewAgentTool(researcher,
+//		syndicate.WithAgentToolParentContext(),
+//		syndicate.WithAgentToolErrorReporting(),
+//	)
+//	manager, err := syndicate.NewAgent(
+//		syndicate.WithTool(researchTool),
+//		// ... other options
+//	)
+func NewAgentTool(agent Agent, options ...AgentToolOption) (Tool, error) {
+	if agent == nil {
+		return nil, errors.New("agent cannot be nil")
+	}
+
+	config := agentToolConfig{
+		name:           invalidToolNameChars.ReplaceAllString(agent.GetName(), "_"),
+		propagateUsage: true,
+	}
+	if described, ok := agent.(interface{ GetDescription() string }); ok {
+		config.description = described.GetDescription()
+	}
+	if config.description == "" {
+		config.description = fmt.Sprintf("Delegates a task to the %s agent and returns its answer.", agent.GetName())
+	}
+
+	for _, option := range options {
+		if err := option(&config); err != nil {
+			return nil, fmt.Errorf("failed to apply agent tool option: %w", err)
+		}
+	}
+	if config.name == "" {
+		return nil, errors.New("tool name is required")
+	}
+
+	schema, err := GenerateRawSchema(agentToolInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate schema: %w", err)
+	}
+
+	return &agentTool{agent: agent, config: config, schema: schema}, nil
+}
+
+func (t *agentTool) GetDefinition() ToolDefinition {
+	return ToolDefinition{
+		Name:        t.config.name,
+		Description: t.config.description,
+		Parameters:  t.schema,
+	}
+}
+
+func (t *agentTool) Execute(args json.RawMessage) (interface{}, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext runs the delegated task on the wrapped agent.
+func (t *agentTool) ExecuteContext(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var input agentToolInput
+	if err := json.Unmarshal(args, &input); err != nil {
+		return nil, fmt.Errorf("invalid arguments for agent tool %s: %w", t.config.name, err)
+	}
+	if input.Task == "" {
+		return nil, errors.New("task is required")
+	}
+
+	result, err := t.delegate(ctx, input)
+	if err != nil {
+		if t.config.reportErrors {
+			return map[string]string{"error": err.Error()}, nil
+		}
+		return nil, err
+	}
+
+	if t.config.propagateUsage {
+		ReportToolUsage(ctx, result.Usage)
+	}
+	if t.config.summarizer != nil {
+		return t.config.summarizer(ctx, result)
+	}
+	return result.Content, nil
+}
+
+// delegate runs a chat turn on the wrapped agent for the given input.
+func (t *agentTool) delegate(ctx context.Context, input agentToolInput) (*ChatResult, error) {
+	tc, _ := ctx.Value(toolCallContextKey{}).(*toolCallContext)
+
+	userName := t.config.userName
+	if userName == "" && tc != nil {
+		userName = tc.agentName
+	}
+	if userName == "" {
+		userName = "agent"
+	}
+
+	prompt := input.Task
+	if input.Context != "" {
+		prompt = fmt.Sprintf("%s\n\nContext:\n%s", prompt, input.Context)
+	}
+	if t.config.parentContext && tc != nil {
+		if transcript := parentTranscript(tc.turn.memory.Get()); transcript != "" {
+			prompt = fmt.Sprintf("%s\n\nConversation so far:\n%s", prompt, transcript)
+		}
+	}
+
+	result, err := chatDetailed(ctx, t.agent, WithUserName(userName), WithInput(prompt))
+	if err != nil {
+		return nil, fmt.Errorf("agent %s failed: %w", t.agent.GetName(), err)
+	}
+	return result, nil
+}
+
+// parentTranscript renders the user and plain assistant messages of a conversation as text,
+// dropping tool traffic that only makes sense to the agent that produced it.
+func parentTranscript(messages []Message) string {
+	var sb strings.Builder
+	for _, msg := range messages {
+		if msg.Role != RoleUser && (msg.Role != RoleAssistant || len(msg.ToolCalls) > 0) {
+			continue
+		}
+		speaker := msg.Name
+		if speaker == "" {
+			speaker = msg.Role
+		}
+		fmt.Fprintf(&sb, "%s: %s\n", speaker, msg.Content)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
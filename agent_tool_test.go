@@ -0,0 +1,132 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// newAgentToolTestAgent crea un agente con un cliente que devuelve las respuestas indicadas.
+func newAgentToolTestAgent(t *testing.T, name string, client LLMClient, options ...AgentOption) Agent {
+	t.Helper()
+	base := []AgentOption{
+		WithClient(client),
+		WithName(name),
+		WithDescription("Researches topics in depth."),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+	}
+	agent, err := NewAgent(append(base, options...)...)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+	return agent
+}
+
+// TestNewAgentToolDefinition verifica el nombre, la descripción y el esquema generados.
+func TestNewAgentToolDefinition(t *testing.T) {
+	sub := newAgentToolTestAgent(t, "Research Agent", &recordingLLMClient{})
+
+	tool, err := NewAgentTool(sub)
+	if err != nil {
+		t.Fatalf("error creando herramienta: %v", err)
+	}
+	def := tool.GetDefinition()
+	if def.Name != "Research_Agent" {
+		t.Errorf("nombre inesperado: %s", def.Name)
+	}
+	if def.Description != "Researches topics in depth." {
+		t.Errorf("descripción inesperada: %s", def.Description)
+	}
+	if !strings.Contains(string(def.Parameters.(json.RawMessage)), `"task"`) {
+		t.Errorf("el esquema no contiene el campo task: %s", def.Parameters)
+	}
+
+	custom, _ := NewAgentTool(sub, WithAgentToolName("research"), WithAgentToolDescription("Busca"))
+	if got := custom.GetDefinition(); got.Name != "research" || got.Description != "Busca" {
+		t.Errorf("las opciones no se aplicaron: %+v", got)
+	}
+
+	if _, err := NewAgentTool(nil); err == nil {
+		t.Error("se esperaba un error con un agente nulo")
+	}
+}
+
+// TestAgentToolDelegation verifica que un agente gestor delegue en el sub-agente y acumule su uso de tokens.
+func TestAgentToolDelegation(t *testing.T) {
+	subResponse := textResponse("París")
+	subResponse.Usage = Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}
+	subClient := &recordingLLMClient{responses: []ChatCompletionResponse{subResponse}}
+	sub := newAgentToolTestAgent(t, "researcher", subClient)
+
+	tool, err := NewAgentTool(sub, WithAgentToolParentContext())
+	if err != nil {
+		t.Fatalf("error creando herramienta: %v", err)
+	}
+
+	managerClient := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "c1", Name: "researcher", Args: json.RawMessage(`{"task":"capital de Francia","context":"geografía"}`)}),
+		textResponse("La capital es París"),
+	}}
+	manager := newAgentToolTestAgent(t, "manager", managerClient, WithTool(tool))
+
+	result, err := manager.(DetailedAgent).ChatDetailed(context.Background(), WithUserName("user"), WithInput("¿Capital de Francia?"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if result.Content != "La capital es París" {
+		t.Errorf("respuesta inesperada: %s", result.Content)
+	}
+	if result.Usage.TotalTokens != 15 {
+		t.Errorf("se esperaba el uso del sub-agente en el resultado, se obtuvo %+v", result.Usage)
+	}
+	if len(result.ToolCalls) != 1 || result.ToolCalls[0].Result != `"París"` {
+		t.Errorf("resultado de herramienta inesperado: %+v", result.ToolCalls)
+	}
+
+	msgs := subClient.Requests()[0].Messages
+	last := msgs[len(msgs)-1]
+	if last.Name != "manager" || !strings.Contains(last.Content, "capital de Francia") || !strings.Contains(last.Content, "geografía") {
+		t.Errorf("el sub-agente recibió un mensaje inesperado: %+v", last)
+	}
+	if !strings.Contains(last.Content, "user: ¿Capital de Francia?") {
+		t.Errorf("el sub-agente no recibió el contexto de la conversación padre: %s", last.Content)
+	}
+}
+
+// TestAgentToolErrorReporting verifica que los fallos del sub-agente se devuelvan como resultado cuando se solicita.
+func TestAgentToolErrorReporting(t *testing.T) {
+	sub := newAgentToolTestAgent(t, "researcher", &fakeLLMClientWithError{})
+
+	failing, _ := NewAgentTool(sub)
+	if _, err := failing.Execute(json.RawMessage(`{"task":"x","context":""}`)); err == nil {
+		t.Error("se esperaba el error del sub-agente")
+	}
+
+	reporting, _ := NewAgentTool(sub, WithAgentToolErrorReporting())
+	result, err := reporting.Execute(json.RawMessage(`{"task":"x","context":""}`))
+	if err != nil {
+		t.Fatalf("no se esperaba error: %v", err)
+	}
+	if m, ok := result.(map[string]string); !ok || !strings.Contains(m["error"], "researcher") {
+		t.Errorf("resultado de error inesperado: %#v", result)
+	}
+}
+
+// TestAgentToolSummarizer verifica que el resumidor reciba el resultado completo del sub-agente.
+func TestAgentToolSummarizer(t *testing.T) {
+	sub := newAgentToolTestAgent(t, "researcher", &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("respuesta larga")}})
+
+	tool, _ := NewAgentTool(sub, WithAgentToolSummarizer(func(ctx context.Context, result *ChatResult) (any, error) {
+		return map[string]any{"answer": result.Content, "rounds": result.Rounds}, nil
+	}))
+	result, err := tool.Execute(json.RawMessage(`{"task":"resume","context":""}`))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	m := result.(map[string]any)
+	if m["answer"] != "respuesta larga" || m["rounds"] != 1 {
+		t.Errorf("resumen inesperado: %v", m)
+	}
+}
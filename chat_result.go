@@ -0,0 +1,125 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// DetailedAgent is an Agent that can report everything that happened during a chat turn.
+// Agents created with NewAgent implement it.
+type DetailedAgent interface {
+	Agent
+	ChatDetailed(ctx context.Context, options ...ChatOption) (*ChatResult, error)
+}
+
+// ChatResult describes a completed chat turn.
+type ChatResult struct {
+	AgentName      string                 // Name of the agent that produced the answer.
+	RunID          string                 // Checkpoint run ID, for Resume; empty when the turn was not checkpointed.
+	Content        string                 // Final answer, as returned by Chat.
+	Messages       []Message              // Messages appended to memory during the turn, in order; not persisted by ephemeral chats.
+	ToolCalls      []ToolCallResult       // Tool calls executed during the turn, in request order.
+	Usage          Usage                  // Token usage aggregated over every round.
+	FinishReason   string                 // Finish reason of the last LLM response.
+	Rounds         int                    // Number of LLM requests made.
+	Continuations  int                    // Continuation requests sent for length-truncated answers.
+	Duration       time.Duration          // Wall-clock duration of the turn.
+	Handoff        *Handoff               // Set when the turn ended by handing the conversation off; Content is then empty.
+	Handoffs       []Handoff              // Handoffs followed by a Syndicate before AgentName produced the answer.
+	Steps          []ReasoningStep        // Thought/action/observation cycles of a ReAct turn.
+	Plan           []PlanStep             // Steps of a plan-and-execute turn, in execution order.
+	Replans        int                    // Times a plan-and-execute turn re-planned after a failed step.
+	Critiques      []Critique             // Reflection rounds, in order; the last one judged the final answer.
+	Request        *ChatCompletionRequest // Request that would have been sent by a dry run; nil otherwise.
+	GuardrailTrips []GuardrailTrip        // Guardrails that fired on the input or the answer, in order.
+}
+
+// ToolCallResult describes a single executed tool call.
+type ToolCallResult struct {
+	Call     ToolCall      // The call as executed, after BeforeToolCall hooks.
+	Result   string        // JSON-encoded result sent back to the model.
+	Skipped  bool          // Whether a hook vetoed the call with ErrSkipToolCall.
+	Duration time.Duration // Time spent running the hooks and the tool.
+}
+
+// ReasoningStep is one thought/action/observation cycle of a ReAct turn.
+type ReasoningStep struct {
+	Thought     string          // The model's reasoning before acting.
+	Action      string          // Tool invoked by the step; empty for the step holding the final answer.
+	ActionInput json.RawMessage // Arguments passed to the tool.
+	Observation string          // Tool result (or error) fed back to the model.
+}
+
+// Critique is the critic's judgement of one draft answer during reflection.
+type Critique struct {
+	Draft    string  // The answer that was judged.
+	Score    float64 // Score from 0 to 10.
+	Feedback string  // What the critic asked to improve.
+	Passed   bool    // Whether the score reached the threshold.
+}
+
+// Plan step statuses.
+const (
+	PlanStepPending   = "pending"
+	PlanStepCompleted = "completed"
+	PlanStepFailed    = "failed"
+)
+
+// PlanStep is one step of a plan-and-execute turn.
+type PlanStep struct {
+	Description string // What the planner asked the step to do.
+	Status      string // PlanStepPending, PlanStepCompleted or PlanStepFailed.
+	Result      string // Output of a completed step.
+	Error       string // Failure of a failed step.
+}
+
+// Add returns the sum of two usage records.
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		PromptTokens:       u.PromptTokens + other.PromptTokens,
+		CompletionTokens:   u.CompletionTokens + other.CompletionTokens,
+		TotalTokens:        u.TotalTokens + other.TotalTokens,
+		CachedPromptTokens: u.CachedPromptTokens + other.CachedPromptTokens,
+	}
+}
+
+// turn holds the state of a single chat turn while it is processed.
+type turn struct {
+	mutex            sync.Mutex // Guards result.Usage and result.Handoff while tools run concurrently.
+	input            string     // The user's input for the turn.
+	memory           Memory
+	systemPrompt     string
+	examples         []Message // Few-shot examples placed between the system prompt and the conversation.
+	dryRun           bool      // Whether the turn stops before its first provider call.
+	tools            []ToolDefinition
+	extraTools       map[string]Tool // Tools passed with WithExtraTools for this turn only.
+	outputGuardrails []Guardrail     // Agent and chat output guardrails, in order.
+	events           *eventEmitter   // Receives the turn's events; nil when nobody listens.
+	scratch          bool            // Whether memory is a throwaway copy, whose updates are not reported.
+	ephemeral        bool            // Whether memory updates are discarded after the turn and not reported.
+	atomic           bool            // Whether memory is a transaction; its updates are reported once it commits.
+	uncommitted      []Message       // Messages written to the transaction of an atomic turn, not yet reported.
+	checkpoint       *checkpointer   // Saves the turn's progress; nil when the turn is not checkpointed.
+	result           *ChatResult
+}
+
+// recordHandoff stores the first handoff requested during the turn; later ones are ignored.
+func (t *turn) recordHandoff(handoff Handoff) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.result.Handoff == nil {
+		t.result.Handoff = &handoff
+	}
+}
+
+// offersTool reports whether a tool with the given name was offered to the model in the turn.
+func (t *turn) offersTool(name string) bool {
+	for _, tool := range t.tools {
+		if tool.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// TestChatDetailed verifica que el resultado detallado refleje rondas, herramientas, uso y mensajes.
+func TestChatDetailed(t *testing.T) {
+	first := toolCallResponse(
+		ToolCall{ID: "c1", Name: "weather", Args: json.RawMessage(`{"city":"Santiago"}`)},
+		ToolCall{ID: "c2", Name: "time", Args: json.RawMessage(`{}`)},
+	)
+	first.Usage = Usage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12}
+	second := textResponse("soleado a las 10")
+	second.Usage = Usage{PromptTokens: 20, CompletionTokens: 5, TotalTokens: 25}
+
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{first, second}}
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("detailed"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithTools(
+			&fakeTool{def: ToolDefinition{Name: "weather"}, execFunc: func(args json.RawMessage) (interface{}, error) {
+				return "soleado", nil
+			}},
+			&fakeTool{def: ToolDefinition{Name: "time"}, execFunc: func(args json.RawMessage) (interface{}, error) {
+				return "10:00", nil
+			}},
+		),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	detailed, ok := agent.(DetailedAgent)
+	if !ok {
+		t.Fatal("el agente debería implementar DetailedAgent")
+	}
+
+	result, err := detailed.ChatDetailed(context.Background(), WithUserName("user"), WithInput("¿clima?"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	if result.Content != "soleado a las 10" || result.AgentName != "detailed" {
+		t.Errorf("contenido o agente inesperado: %+v", result)
+	}
+	if result.Rounds != 2 || result.FinishReason != FinishReasonStop {
+		t.Errorf("rondas o finish reason inesperados: %d %s", result.Rounds, result.FinishReason)
+	}
+	if result.Usage.TotalTokens != 37 || result.Usage.PromptTokens != 30 || result.Usage.CompletionTokens != 7 {
+		t.Errorf("uso agregado inesperado: %+v", result.Usage)
+	}
+	if len(result.ToolCalls) != 2 || result.ToolCalls[0].Call.Name != "weather" || result.ToolCalls[0].Result != `"soleado"` {
+		t.Errorf("llamadas a herramientas inesperadas: %+v", result.ToolCalls)
+	}
+	if result.Duration <= 0 {
+		t.Error("se esperaba una duración positiva")
+	}
+
+	// usuario, llamada a herramientas, 2 resultados, respuesta final
+	roles := []string{RoleUser, RoleAssistant, RoleTool, RoleTool, RoleAssistant}
+	if len(result.Messages) != len(roles) {
+		t.Fatalf("se esperaban %d mensajes, se obtuvieron %d", len(roles), len(result.Messages))
+	}
+	for i, role := range roles {
+		if result.Messages[i].Role != role {
+			t.Errorf("mensaje %d: se esperaba rol %s, se obtuvo %s", i, role, result.Messages[i].Role)
+		}
+	}
+}
+
+// TestExecuteAgentDetailed verifica el resultado detallado en el syndicate, incluso para agentes simples.
+func TestExecuteAgentDetailed(t *testing.T) {
+	response := textResponse("hola")
+	response.Usage = Usage{TotalTokens: 7}
+	real, _ := NewAgent(
+		WithClient(&recordingLLMClient{responses: []ChatCompletionResponse{response}}),
+		WithName("real"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+	)
+	simple := newSyndicateTestAgent("simple", "->simple")
+
+	syn, err := NewSyndicate(WithAgents(real, simple))
+	if err != nil {
+		t.Fatalf("error creando syndicate: %v", err)
+	}
+	s := syn.(DetailedSyndicate)
+
+	ctx := context.Background()
+	result, err := s.ExecuteAgentDetailed(ctx, "real", WithExecuteUserName("user"), WithExecuteInput("hola"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if result.Content != "hola" || result.Usage.TotalTokens != 7 || result.Rounds != 1 {
+		t.Errorf("resultado detallado inesperado: %+v", result)
+	}
+
+	result, err = s.ExecuteAgentDetailed(ctx, "simple", WithExecuteUserName("user"), WithExecuteInput("hola"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if result.Content != "hola ->simple" || result.AgentName != "simple" {
+		t.Errorf("resultado del agente simple inesperado: %+v", result)
+	}
+
+	if _, err := s.ExecuteAgentDetailed(ctx, "missing", WithExecuteUserName("user"), WithExecuteInput("hola")); err == nil {
+		t.Error("se esperaba error por agente inexistente")
+	}
+}
@@ -0,0 +1,525 @@
+package syndicate
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrCheckpointNotFound is returned by CheckpointStore.Load when no checkpoint has the requested run ID.
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// Checkpoint stages.
+const (
+	// CheckpointStageRunning means every message produced so far is stored and the next step is a model request.
+	CheckpointStageRunning = "running"
+	// CheckpointStageToolCalls means the model requested tool calls that have not all completed.
+	CheckpointStageToolCalls = "tool_calls"
+	// CheckpointStageCompleted means the run finished; Result holds its final result.
+	CheckpointStageCompleted = "completed"
+)
+
+// Checkpoint is the saved state of an agent chat turn or of a pipeline run.
+type Checkpoint struct {
+	RunID     string            `json:"run_id"`
+	AgentName string            `json:"agent_name,omitempty"` // Agent of a chat turn; empty for pipelines.
+	Stage     string            `json:"stage"`
+	Request   CheckpointRequest `json:"request"`
+	// Result holds the progress of the turn: messages produced, tool calls, usage and rounds so far.
+	Result *ChatResult `json:"result,omitempty"`
+	// PendingToolCalls are the tool calls requested in the current round (CheckpointStageToolCalls),
+	// and CompletedToolCalls those that already succeeded, which are not executed again on resume.
+	PendingToolCalls   []ToolCall       `json:"pending_tool_calls,omitempty"`
+	CompletedToolCalls []ToolCallResult `json:"completed_tool_calls,omitempty"`
+	Pipeline           *PipelineState   `json:"pipeline,omitempty"` // Progress of a pipeline run.
+	Error              string           `json:"error,omitempty"`    // Error that interrupted the run, if it failed.
+	UpdatedAt          time.Time        `json:"updated_at"`
+}
+
+// CheckpointRequest holds the parameters of a checkpointed chat request needed to resume it.
+type CheckpointRequest struct {
+	UserName           string         `json:"user_name"`
+	Input              string         `json:"input"`
+	ImageURLs          []string       `json:"image_urls,omitempty"`
+	SessionID          string         `json:"session_id,omitempty"`
+	AdditionalMessages [][]Message    `json:"additional_messages,omitempty"`
+	Metadata           map[string]any `json:"metadata,omitempty"`
+	EnabledTools       []string       `json:"enabled_tools,omitempty"`
+	DisabledTools      []string       `json:"disabled_tools,omitempty"`
+	// ExtraTools are the names of the tools passed with WithExtraTools, which cannot be saved;
+	// Resume requires them to be passed again.
+	ExtraTools []string `json:"extra_tools,omitempty"`
+}
+
+// PipelineState is the progress of a pipeline run.
+type PipelineState struct {
+	Agents    []string `json:"agents"`               // Agents of the pipeline, in order.
+	Step      int      `json:"step"`                 // Index of the next agent to run.
+	UserName  string   `json:"user_name"`            // User the pipeline runs for.
+	Input     string   `json:"input"`                // Input of the next agent; the final answer once completed.
+	ImageURLs []string `json:"image_urls,omitempty"` // Images for the first agent.
+}
+
+// CheckpointStore persists checkpoints by run ID. Save replaces any checkpoint with the same
+// run ID and must not keep a reference to the checkpoint, which is reused by the caller.
+type CheckpointStore interface {
+	Save(ctx context.Context, checkpoint *Checkpoint) error
+	Load(ctx context.Context, runID string) (*Checkpoint, error)
+	Delete(ctx context.Context, runID string) error
+}
+
+// MemoryCheckpointStore keeps checkpoints in memory. It survives failed turns, not process crashes;
+// use it for tests or together with a process-level supervisor.
+type MemoryCheckpointStore struct {
+	mutex       sync.RWMutex
+	checkpoints map[string][]byte
+}
+
+// NewMemoryCheckpointStore creates an empty in-memory checkpoint store.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string][]byte)}
+}
+
+// Save stores a copy of the checkpoint.
+func (s *MemoryCheckpointStore) Save(ctx context.Context, checkpoint *Checkpoint) error {
+	if checkpoint.RunID == "" {
+		return errors.New("run ID cannot be empty")
+	}
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.checkpoints[checkpoint.RunID] = data
+	return nil
+}
+
+// Load returns a copy of the checkpoint with the given run ID.
+func (s *MemoryCheckpointStore) Load(ctx context.Context, runID string) (*Checkpoint, error) {
+	s.mutex.RLock()
+	data, ok := s.checkpoints[runID]
+	s.mutex.RUnlock()
+	if !ok {
+		return nil, ErrCheckpointNotFound
+	}
+	return decodeCheckpoint(data)
+}
+
+// Delete removes the checkpoint with the given run ID, if any.
+func (s *MemoryCheckpointStore) Delete(ctx context.Context, runID string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.checkpoints, runID)
+	return nil
+}
+
+// FileCheckpointStore keeps each checkpoint in a JSON file of a directory.
+// Files are replaced atomically, so a crash while saving leaves the previous checkpoint intact.
+type FileCheckpointStore struct {
+	dir string
+}
+
+// NewFileCheckpointStore creates a store in dir, creating the directory if needed.
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	if dir == "" {
+		return nil, errors.New("checkpoint directory cannot be empty")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+// path returns the file of a run ID; run IDs are escaped so they can contain slashes.
+func (s *FileCheckpointStore) path(runID string) string {
+	return filepath.Join(s.dir, url.PathEscape(runID)+".json")
+}
+
+// Save writes the checkpoint to a temporary file and renames it over the previous one.
+func (s *FileCheckpointStore) Save(ctx context.Context, checkpoint *Checkpoint) error {
+	if checkpoint.RunID == "" {
+		return errors.New("run ID cannot be empty")
+	}
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+
+	file, err := os.CreateTemp(s.dir, ".checkpoint-*")
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(file.Name(), s.path(checkpoint.RunID)); err != nil {
+		return fmt.Errorf("failed to replace checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Load reads the checkpoint with the given run ID.
+func (s *FileCheckpointStore) Load(ctx context.Context, runID string) (*Checkpoint, error) {
+	data, err := os.ReadFile(s.path(runID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrCheckpointNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	return decodeCheckpoint(data)
+}
+
+// Delete removes the checkpoint file with the given run ID, if any.
+func (s *FileCheckpointStore) Delete(ctx context.Context, runID string) error {
+	if err := os.Remove(s.path(runID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete checkpoint: %w", err)
+	}
+	return nil
+}
+
+// decodeCheckpoint parses a checkpoint saved as JSON.
+func decodeCheckpoint(data []byte) (*Checkpoint, error) {
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+// newRunID returns a random run ID.
+func newRunID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("run-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// ResumableAgent is an Agent whose checkpointed chat turns can be resumed.
+// Agents created with NewAgent implement it.
+type ResumableAgent interface {
+	DetailedAgent
+	// Resume continues the chat turn saved under runID from its last checkpoint. Tool calls that
+	// already succeeded are not executed again, and a completed turn returns its saved result.
+	// Options add to the saved request, for example event handlers; the extra tools of the
+	// request must be passed again.
+	Resume(ctx context.Context, runID string, options ...ChatOption) (*ChatResult, error)
+}
+
+// ResumableSyndicate is a Syndicate whose checkpointed pipeline runs can be resumed.
+// Syndicates created with NewSyndicate implement it.
+type ResumableSyndicate interface {
+	Syndicate
+	// ResumePipeline continues the pipeline run saved under runID from its last completed agent.
+	// Only options adding event handlers apply; the rest of the request is restored from the
+	// checkpoint. A completed run returns its saved answer.
+	ResumePipeline(ctx context.Context, runID string, options ...PipelineOption) (string, error)
+}
+
+// WithCheckpointStore saves the progress of every chat turn to store, so an interrupted turn can be
+// resumed with Resume. Turns are checkpointed when they start, when the model requests tool calls,
+// when each tool call succeeds and when they finish. Ephemeral chats are not checkpointed.
+//
+// The native tool-calling strategy resumes from the last completed tool call; other strategies
+// resume from the start of the turn, keeping the user's message.
+func WithCheckpointStore(store CheckpointStore) AgentOption {
+	return func(a *agent) error {
+		if store == nil {
+			return errors.New("checkpoint store cannot be nil")
+		}
+		a.checkpoints = store
+		return nil
+	}
+}
+
+// WithRunID sets the run ID under which the chat turn is checkpointed. Without it, checkpointed
+// turns get a random run ID, reported in ChatResult.RunID.
+func WithRunID(runID string) ChatOption {
+	return func(r *chatRequest) {
+		r.runID = runID
+	}
+}
+
+// WithChatCheckpointStore checkpoints this chat request in store, instead of the agent's store.
+func WithChatCheckpointStore(store CheckpointStore) ChatOption {
+	return func(r *chatRequest) {
+		r.checkpoints = store
+	}
+}
+
+// checkpointStore returns the store for a chat request, or nil when it is not checkpointed.
+func (a *agent) checkpointStore(req *chatRequest) CheckpointStore {
+	if req.checkpoints != nil {
+		return req.checkpoints
+	}
+	return a.checkpoints
+}
+
+// checkpointer saves the progress of a turn.
+type checkpointer struct {
+	store CheckpointStore
+	mutex sync.Mutex // Serializes saves while tools run concurrently.
+	cp    *Checkpoint
+}
+
+// newCheckpointer returns a checkpointer continuing cp, or starting a new checkpoint when cp is nil.
+// It returns nil when the request is not checkpointed.
+func (a *agent) newCheckpointer(req *chatRequest, cp *Checkpoint) *checkpointer {
+	store := a.checkpointStore(req)
+	if store == nil {
+		return nil
+	}
+	if cp == nil {
+		runID := req.runID
+		if runID == "" {
+			runID = newRunID()
+		}
+		cp = &Checkpoint{
+			RunID:     runID,
+			AgentName: a.name,
+			Request: CheckpointRequest{
+				UserName:           req.userName,
+				Input:              req.input,
+				ImageURLs:          req.imageURLs,
+				SessionID:          req.sessionID,
+				AdditionalMessages: req.additionalMessages,
+				Metadata:           req.metadata,
+				EnabledTools:       req.enabledTools,
+				DisabledTools:      req.disabledTools,
+				ExtraTools:         toolNames(req.extraTools),
+			},
+		}
+	}
+	return &checkpointer{store: store, cp: cp}
+}
+
+// toolNames returns the names of tools.
+func toolNames(tools []Tool) []string {
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.GetDefinition().Name)
+	}
+	return names
+}
+
+// save stores the checkpoint with a snapshot of the turn result. The caller holds c.mutex.
+func (c *checkpointer) save(ctx context.Context, t *turn) error {
+	t.mutex.Lock()
+	result := *t.result
+	t.mutex.Unlock()
+
+	c.cp.Result = &result
+	c.cp.UpdatedAt = time.Now()
+	if err := c.store.Save(ctx, c.cp); err != nil {
+		return fmt.Errorf("failed to save checkpoint %s: %w", c.cp.RunID, err)
+	}
+	return nil
+}
+
+// checkpoint saves the turn at the given stage.
+func (a *agent) checkpoint(ctx context.Context, t *turn, stage string) error {
+	c := t.checkpoint
+	if c == nil {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.cp.Stage = stage
+	c.cp.PendingToolCalls = nil
+	c.cp.CompletedToolCalls = nil
+	c.cp.Error = ""
+	return c.save(ctx, t)
+}
+
+// checkpointToolCalls saves the tool calls requested by the model before they run.
+func (a *agent) checkpointToolCalls(ctx context.Context, t *turn, calls []ToolCall) error {
+	c := t.checkpoint
+	if c == nil {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.cp.Stage = CheckpointStageToolCalls
+	c.cp.PendingToolCalls = calls
+	c.cp.CompletedToolCalls = nil
+	return c.save(ctx, t)
+}
+
+// checkpointToolCall records a tool call that succeeded, so it is not executed again on resume.
+func (a *agent) checkpointToolCall(ctx context.Context, t *turn, result ToolCallResult) error {
+	c := t.checkpoint
+	if c == nil {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.cp.CompletedToolCalls = append(c.cp.CompletedToolCalls, result)
+	return c.save(ctx, t)
+}
+
+// checkpointFailure records the error that interrupted the turn, keeping its last stage.
+func (a *agent) checkpointFailure(ctx context.Context, t *turn, err error) {
+	c := t.checkpoint
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.cp.Error = err.Error()
+	if saveErr := c.store.Save(ctx, c.cp); saveErr != nil {
+		a.logger.WarnContext(ctx, "failed to save checkpoint",
+			slog.String("agent", a.name),
+			slog.String("run_id", c.cp.RunID),
+			slog.Any("error", saveErr),
+		)
+	}
+}
+
+// Resume continues a checkpointed chat turn; see ResumableAgent.
+func (a *agent) Resume(ctx context.Context, runID string, options ...ChatOption) (*ChatResult, error) {
+	return a.track(ctx, options, func(ctx context.Context, req *chatRequest, events *eventEmitter) (*ChatResult, error) {
+		return a.resume(ctx, runID, req, events)
+	})
+}
+
+// resume implements Resume; errors are reported by the caller.
+func (a *agent) resume(ctx context.Context, runID string, req *chatRequest, events *eventEmitter) (*ChatResult, error) {
+	store := a.checkpointStore(req)
+	if store == nil {
+		return nil, errors.New("no checkpoint store configured")
+	}
+	cp, err := store.Load(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint %s: %w", runID, err)
+	}
+	if cp.AgentName != a.name {
+		return nil, fmt.Errorf("checkpoint %s does not belong to agent %s", runID, a.name)
+	}
+	if cp.Result == nil {
+		cp.Result = &ChatResult{AgentName: a.name}
+	}
+	if cp.Stage == CheckpointStageCompleted {
+		return cp.Result, nil
+	}
+
+	req.userName = cp.Request.UserName
+	req.input = cp.Request.Input
+	req.imageURLs = cp.Request.ImageURLs
+	req.sessionID = cp.Request.SessionID
+	req.additionalMessages = cp.Request.AdditionalMessages
+	req.metadata = cp.Request.Metadata
+	req.enabledTools = append(append([]string(nil), cp.Request.EnabledTools...), req.enabledTools...)
+	req.disabledTools = append(append([]string(nil), cp.Request.DisabledTools...), req.disabledTools...)
+	req.checkpoints = store
+	extraTools, err := extraToolSet(req.extraTools)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range cp.Request.ExtraTools {
+		if _, ok := extraTools[name]; !ok {
+			return nil, fmt.Errorf("checkpoint %s needs extra tool %s; pass it again with WithExtraTools", runID, name)
+		}
+	}
+
+	a.logger.InfoContext(ctx, "resuming chat",
+		slog.String("agent", a.name),
+		slog.String("run_id", runID),
+		slog.String("stage", cp.Stage),
+		slog.Int("completed_tool_calls", len(cp.CompletedToolCalls)),
+	)
+
+	t := &turn{
+		input:            req.input,
+		extraTools:       extraTools,
+		outputGuardrails: joinGuardrails(a.outputGuardrails, req.outputGuardrails),
+		events:           events,
+		result:           cp.Result,
+	}
+	return a.execute(ctx, t, req, cp, func(ctx context.Context) error {
+		return a.resumeTurn(ctx, t, req, cp)
+	})
+}
+
+// resumeTurn restores the messages of an interrupted turn, finishes its pending tool calls and
+// runs the agent's strategy from there within the turn timeout.
+func (a *agent) resumeTurn(ctx context.Context, t *turn, req *chatRequest, cp *Checkpoint) error {
+	// The interrupted turn was rolled back (atomic turns) or lost with the process; replay its
+	// messages, unless the memory kept them. They are written as in a new turn, with hooks and events.
+	if !endsWithMessages(t.memory.Get(), t.result.Messages) {
+		for i, message := range t.result.Messages {
+			stored, err := a.store(ctx, t, message)
+			if err != nil {
+				return err
+			}
+			t.result.Messages[i] = stored
+		}
+	}
+
+	tools, err := a.prepareTools(ctx, t, req)
+	if err != nil {
+		return err
+	}
+	t.tools = tools
+
+	ctx, cancel := context.WithTimeout(ctx, a.turnTimeout(req))
+	defer cancel()
+
+	if cp.Stage == CheckpointStageToolCalls {
+		if err := a.runToolCalls(ctx, t, cp.PendingToolCalls, cp.CompletedToolCalls); err != nil {
+			return err
+		}
+		if t.result.Handoff != nil {
+			return nil
+		}
+	}
+
+	a.mutex.RLock()
+	messages := a.prepareMessages(t)
+	// Additional messages only belong to the first request of the turn.
+	if t.result.Rounds == 0 {
+		for _, additional := range req.additionalMessages {
+			messages = append(messages, additional...)
+		}
+	}
+	a.mutex.RUnlock()
+
+	return a.strategy.run(ctx, a, t, messages)
+}
+
+// endsWithMessages reports whether history ends with the given messages.
+func endsWithMessages(history, messages []Message) bool {
+	if len(messages) > len(history) {
+		return false
+	}
+	tail := history[len(history)-len(messages):]
+	for i, message := range messages {
+		if tail[i].Role != message.Role || tail[i].Content != message.Content || tail[i].ToolCallID != message.ToolCallID {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,376 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// countingTool cuenta sus ejecuciones y falla mientras fail sea verdadero.
+func countingTool(name string, runs *atomic.Int32, fail *atomic.Bool) Tool {
+	return &fakeTool{
+		def: ToolDefinition{Name: name, Description: "Herramienta de prueba", Parameters: json.RawMessage(`{"type":"object"}`)},
+		execFunc: func(args json.RawMessage) (interface{}, error) {
+			runs.Add(1)
+			if fail != nil && fail.Load() {
+				return nil, errors.New("proceso interrumpido")
+			}
+			return name + " ok", nil
+		},
+	}
+}
+
+// TestAgentResumeSkipsCompletedTools verifica que al reanudar no se repitan las herramientas que ya terminaron.
+func TestAgentResumeSkipsCompletedTools(t *testing.T) {
+	var reserveRuns, chargeRuns atomic.Int32
+	var crash atomic.Bool
+	crash.Store(true)
+
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(
+			ToolCall{ID: "c1", Name: "reserve", Args: json.RawMessage(`{}`)},
+			ToolCall{ID: "c2", Name: "charge", Args: json.RawMessage(`{}`)},
+		),
+		textResponse("reserva confirmada"),
+	}}
+	store := NewMemoryCheckpointStore()
+	mem := NewSimpleMemory()
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("booking"),
+		WithMemory(mem),
+		WithModel("gpt-4o"),
+		WithTools(countingTool("reserve", &reserveRuns, nil), countingTool("charge", &chargeRuns, &crash)),
+		WithCheckpointStore(store),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	_, err = agent.Chat(context.Background(), WithUserName("user"), WithInput("reserva"), WithRunID("run-1"))
+	if err == nil {
+		t.Fatal("se esperaba que el turno fallara")
+	}
+	cp, err := store.Load(context.Background(), "run-1")
+	if err != nil {
+		t.Fatalf("no se guardó el checkpoint: %v", err)
+	}
+	if cp.Stage != CheckpointStageToolCalls || len(cp.PendingToolCalls) != 2 || len(cp.CompletedToolCalls) != 1 || cp.Error == "" {
+		t.Fatalf("checkpoint inesperado: %+v", cp)
+	}
+	if len(mem.Get()) != 0 {
+		t.Errorf("el turno atómico fallido no debía escribir en memoria: %+v", mem.Get())
+	}
+
+	crash.Store(false)
+	resumable := agent.(ResumableAgent)
+	result, err := resumable.Resume(context.Background(), "run-1")
+	if err != nil {
+		t.Fatalf("error reanudando: %v", err)
+	}
+	if result.Content != "reserva confirmada" || result.RunID != "run-1" {
+		t.Errorf("resultado inesperado: %+v", result)
+	}
+	if reserveRuns.Load() != 1 || chargeRuns.Load() != 2 {
+		t.Errorf("ejecuciones inesperadas: reserve=%d charge=%d", reserveRuns.Load(), chargeRuns.Load())
+	}
+	if result.Rounds != 2 || len(result.ToolCalls) != 2 {
+		t.Errorf("rondas o herramientas inesperadas: %d %+v", result.Rounds, result.ToolCalls)
+	}
+	if msgs := mem.Get(); len(msgs) != 5 || msgs[0].Content != "reserva" || msgs[4].Content != "reserva confirmada" {
+		t.Errorf("memoria inesperada: %+v", msgs)
+	}
+
+	// Un turno completado devuelve su resultado sin volver a llamar al modelo.
+	again, err := resumable.Resume(context.Background(), "run-1")
+	if err != nil || again.Content != "reserva confirmada" || len(client.Requests()) != 2 {
+		t.Errorf("reanudar un turno completado no debía repetirlo: %+v %v (%d llamadas)", again, err, len(client.Requests()))
+	}
+
+	if _, err := resumable.Resume(context.Background(), "desconocido"); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("se esperaba ErrCheckpointNotFound, se obtuvo %v", err)
+	}
+}
+
+// TestAgentResumeReplaysMemoryEvents verifica que los mensajes restaurados pasen por los hooks y emitan memory_updated.
+func TestAgentResumeReplaysMemoryEvents(t *testing.T) {
+	var runs atomic.Int32
+	var crash atomic.Bool
+	crash.Store(true)
+
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "c1", Name: "charge", Args: json.RawMessage(`{}`)}),
+		textResponse("cobrado"),
+	}}
+	var hookCalls atomic.Int32
+	store := NewMemoryCheckpointStore()
+	mem := NewSimpleMemory()
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("billing"),
+		WithMemory(mem),
+		WithModel("gpt-4o"),
+		WithTools(countingTool("charge", &runs, &crash)),
+		WithCheckpointStore(store),
+		WithHooks(Hooks{BeforeMemoryWrite: func(ctx context.Context, msg *Message) error {
+			hookCalls.Add(1)
+			return nil
+		}}),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := agent.Chat(ctx, WithUserName("user"), WithInput("cobra"), WithRunID("run-events")); err == nil {
+		t.Fatal("se esperaba que el turno fallara")
+	}
+	crash.Store(false)
+	hookCalls.Store(0)
+
+	events := &eventRecorder{}
+	if _, err := agent.(ResumableAgent).Resume(ctx, "run-events", WithChatEventHandler(events.handle)); err != nil {
+		t.Fatalf("error reanudando: %v", err)
+	}
+
+	var updated []Message
+	for _, event := range events.events {
+		if event.Type == EventMemoryUpdated {
+			updated = append(updated, *event.Message)
+		}
+	}
+	msgs := mem.Get()
+	if len(updated) != len(msgs) || len(msgs) != 4 {
+		t.Fatalf("se esperaba un memory_updated por mensaje: %d eventos, %d mensajes", len(updated), len(msgs))
+	}
+	for i := range msgs {
+		if updated[i].Role != msgs[i].Role || updated[i].Content != msgs[i].Content {
+			t.Errorf("evento %d inesperado: %+v, en memoria %+v", i, updated[i], msgs[i])
+		}
+	}
+	if hookCalls.Load() != 4 {
+		t.Errorf("se esperaban 4 llamadas al hook de memoria, hubo %d", hookCalls.Load())
+	}
+}
+
+// TestAgentResumeRestoresToolFilters verifica que al reanudar se conserven los filtros de herramientas y se exijan las herramientas extra.
+func TestAgentResumeRestoresToolFilters(t *testing.T) {
+	var lookupRuns, chargeRuns atomic.Int32
+	var crash atomic.Bool
+	crash.Store(true)
+
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "c1", Name: "lookup", Args: json.RawMessage(`{}`)}),
+		textResponse("pedido encontrado"),
+	}}
+	store := NewMemoryCheckpointStore()
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("orders"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithTools(countingTool("charge", &chargeRuns, nil), countingTool("refund", &chargeRuns, nil)),
+		WithCheckpointStore(store),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+	lookup := countingTool("lookup", &lookupRuns, &crash)
+
+	ctx := context.Background()
+	_, err = agent.Chat(ctx, WithUserName("user"), WithInput("busca mi pedido"), WithRunID("run-filters"),
+		WithExtraTools(lookup), WithDisabledTools("charge"))
+	if err == nil {
+		t.Fatal("se esperaba que el turno fallara")
+	}
+	cp, err := store.Load(ctx, "run-filters")
+	if err != nil {
+		t.Fatalf("no se guardó el checkpoint: %v", err)
+	}
+	if len(cp.Request.DisabledTools) != 1 || len(cp.Request.ExtraTools) != 1 || cp.Request.ExtraTools[0] != "lookup" {
+		t.Fatalf("request del checkpoint inesperado: %+v", cp.Request)
+	}
+
+	crash.Store(false)
+	resumable := agent.(ResumableAgent)
+	if _, err := resumable.Resume(ctx, "run-filters"); err == nil || !strings.Contains(err.Error(), "needs extra tool lookup") {
+		t.Fatalf("se esperaba un error por la herramienta extra faltante, se obtuvo %v", err)
+	}
+	result, err := resumable.Resume(ctx, "run-filters", WithExtraTools(lookup))
+	if err != nil {
+		t.Fatalf("error reanudando: %v", err)
+	}
+	if result.Content != "pedido encontrado" || lookupRuns.Load() != 2 {
+		t.Errorf("resultado inesperado: %+v (lookup=%d)", result, lookupRuns.Load())
+	}
+	var offered []string
+	for _, tool := range client.Requests()[1].Tools {
+		offered = append(offered, tool.Name)
+	}
+	if strings.Join(offered, ",") != "lookup,refund" {
+		t.Errorf("herramientas ofrecidas al reanudar: %v", offered)
+	}
+}
+
+// TestFileCheckpointStore verifica que el almacén en archivos guarde, cargue y borre checkpoints.
+func TestFileCheckpointStore(t *testing.T) {
+	store, err := NewFileCheckpointStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("error creando almacén: %v", err)
+	}
+	ctx := context.Background()
+
+	cp := &Checkpoint{
+		RunID:     "pipeline/step-0",
+		AgentName: "writer",
+		Stage:     CheckpointStageToolCalls,
+		Request:   CheckpointRequest{UserName: "user", Input: "hola"},
+		Result:    &ChatResult{AgentName: "writer", Rounds: 1, Usage: Usage{TotalTokens: 7}},
+		PendingToolCalls: []ToolCall{
+			{ID: "c1", Name: "search", Args: json.RawMessage(`{"q":"go"}`)},
+		},
+	}
+	if err := store.Save(ctx, cp); err != nil {
+		t.Fatalf("error guardando: %v", err)
+	}
+	loaded, err := store.Load(ctx, "pipeline/step-0")
+	if err != nil {
+		t.Fatalf("error cargando: %v", err)
+	}
+	if loaded.Stage != cp.Stage || loaded.Request.Input != "hola" || loaded.Result.Usage.TotalTokens != 7 ||
+		string(loaded.PendingToolCalls[0].Args) != `{"q":"go"}` {
+		t.Errorf("checkpoint cargado inesperado: %+v", loaded)
+	}
+
+	if err := store.Delete(ctx, "pipeline/step-0"); err != nil {
+		t.Fatalf("error borrando: %v", err)
+	}
+	if _, err := store.Load(ctx, "pipeline/step-0"); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("se esperaba ErrCheckpointNotFound, se obtuvo %v", err)
+	}
+}
+
+// TestResumePipeline verifica que el pipeline continúe desde el agente interrumpido sin repetir los anteriores.
+func TestResumePipeline(t *testing.T) {
+	var lookupRuns atomic.Int32
+	var crash atomic.Bool
+	crash.Store(true)
+
+	writerClient := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("borrador")}}
+	editorClient := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "c1", Name: "lookup", Args: json.RawMessage(`{}`)}),
+		textResponse("versión final"),
+	}}
+	writer := newHandoffTestAgent(t, "writer", writerClient)
+	editor, err := NewAgent(
+		WithClient(editorClient),
+		WithName("editor"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithTools(countingTool("lookup", &lookupRuns, &crash)),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	store := NewMemoryCheckpointStore()
+	syn, err := NewSyndicate(
+		WithAgents(writer, editor),
+		WithPipeline("writer", "editor"),
+		WithSyndicateCheckpointStore(store),
+	)
+	if err != nil {
+		t.Fatalf("error creando sindicato: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := syn.ExecutePipeline(ctx, WithPipelineUserName("user"), WithPipelineInput("escribe"), WithPipelineRunID("p1")); err == nil {
+		t.Fatal("se esperaba que el pipeline fallara")
+	}
+	cp, err := store.Load(ctx, "p1")
+	if err != nil || cp.Pipeline.Step != 1 || cp.Pipeline.Input != "borrador" {
+		t.Fatalf("checkpoint del pipeline inesperado: %+v %v", cp, err)
+	}
+	if step, err := store.Load(ctx, "p1/step-1"); err != nil || step.Stage != CheckpointStageToolCalls {
+		t.Fatalf("checkpoint del editor inesperado: %+v %v", step, err)
+	}
+
+	crash.Store(false)
+	final, err := syn.(ResumableSyndicate).ResumePipeline(ctx, "p1")
+	if err != nil {
+		t.Fatalf("error reanudando el pipeline: %v", err)
+	}
+	if final != "versión final" {
+		t.Errorf("respuesta final inesperada: %q", final)
+	}
+	if len(writerClient.Requests()) != 1 || len(editorClient.Requests()) != 2 {
+		t.Errorf("llamadas inesperadas: writer=%d editor=%d", len(writerClient.Requests()), len(editorClient.Requests()))
+	}
+	if lookupRuns.Load() != 2 {
+		t.Errorf("se esperaban 2 ejecuciones de lookup, hubo %d", lookupRuns.Load())
+	}
+
+	if again, err := syn.(ResumableSyndicate).ResumePipeline(ctx, "p1"); err != nil || again != "versión final" {
+		t.Errorf("un pipeline completado debía devolver su respuesta: %q %v", again, err)
+	}
+}
+
+// TestResumePipelinePendingHandoff verifica que al reanudar se ofrezcan las herramientas de handoff de una llamada pendiente.
+func TestResumePipelinePendingHandoff(t *testing.T) {
+	var crash atomic.Bool
+	crash.Store(true)
+
+	triageClient := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "h1", Name: "transfer_to_billing", Args: json.RawMessage(`{"note":"factura"}`)}),
+	}}
+	billingClient := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("Factura enviada")}}
+	triage, err := NewAgent(
+		WithClient(triageClient),
+		WithName("triage"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithHooks(Hooks{BeforeToolCall: func(ctx context.Context, call *ToolCall) error {
+			if crash.Load() {
+				return errors.New("proceso interrumpido")
+			}
+			return nil
+		}}),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	store := NewMemoryCheckpointStore()
+	syn, err := NewSyndicate(
+		WithAgents(triage, newHandoffTestAgent(t, "billing", billingClient)),
+		WithHandoffs("triage", "billing"),
+		WithPipeline("triage"),
+		WithSyndicateCheckpointStore(store),
+	)
+	if err != nil {
+		t.Fatalf("error creando sindicato: %v", err)
+	}
+
+	ctx := context.Background()
+	if _, err := syn.ExecutePipeline(ctx, WithPipelineUserName("user"), WithPipelineInput("quiero mi factura"), WithPipelineRunID("p2")); err == nil {
+		t.Fatal("se esperaba que el pipeline fallara")
+	}
+	if step, err := store.Load(ctx, "p2/step-0"); err != nil || step.Stage != CheckpointStageToolCalls {
+		t.Fatalf("checkpoint de triage inesperado: %+v %v", step, err)
+	}
+
+	crash.Store(false)
+	final, err := syn.(ResumableSyndicate).ResumePipeline(ctx, "p2")
+	if err != nil {
+		t.Fatalf("error reanudando el pipeline: %v", err)
+	}
+	if final != "Factura enviada" {
+		t.Errorf("respuesta final inesperada: %q", final)
+	}
+	if len(triageClient.Requests()) != 1 || len(billingClient.Requests()) != 1 {
+		t.Errorf("llamadas inesperadas: triage=%d billing=%d", len(triageClient.Requests()), len(billingClient.Requests()))
+	}
+}
@@ -0,0 +1,604 @@
+// Package config builds agents and syndicates from declarative YAML or JSON files.
+//
+// A configuration file declares providers (LLM clients), agents and, optionally, a syndicate:
+//
+//	providers:
+//	  openai:
+//	    type: openai
+//	    api_key: ${OPENAI_API_KEY}
+//	agents:
+//	  - name: triage
+//	    provider: openai
+//	    model: gpt-4o
+//	    system_prompt_file: prompts/triage.md
+//	    tools: [lookup_order]
+//	  - name: billing
+//	    provider: openai
+//	    model: gpt-4o-mini
+//	    system_prompt_template: "You help {{.UserName}} with invoices."
+//	    temperature: 0.2
+//	syndicate:
+//	  handoffs:
+//	    triage: [billing]
+//
+// Values may reference environment variables as ${VAR} or ${VAR:-default}; write $${VAR} for a
+// literal ${VAR}. System prompts and prompt templates are never expanded. Tools, provider
+// types and memory types are resolved by name through a Registry. Problems are reported
+// together as Errors, each with the file position it refers to.
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	syndicate "github.com/Dieg0Code/syndicate-go"
+	"gopkg.in/yaml.v3"
+)
+
+// File is the structure of a configuration file.
+type File struct {
+	Providers map[string]ProviderConfig `yaml:"providers"`
+	Agents    []AgentConfig             `yaml:"agents"`
+	Syndicate *SyndicateConfig          `yaml:"syndicate"`
+}
+
+// ProviderConfig declares an LLM client that agents reference by its key in File.Providers.
+type ProviderConfig struct {
+	Type    string            `yaml:"type"`     // Provider type registered in the Registry.
+	APIKey  string            `yaml:"api_key"`  // API key; usually an environment variable reference.
+	BaseURL string            `yaml:"base_url"` // Endpoint, for providers that need one.
+	Options map[string]string `yaml:"options"`  // Settings for custom provider types.
+}
+
+// AgentConfig declares an agent. At most one of SystemPrompt, SystemPromptFile and
+// SystemPromptTemplate may be set.
+type AgentConfig struct {
+	Name                 string                `yaml:"name"`
+	Description          string                `yaml:"description"`
+	Provider             string                `yaml:"provider"`
+	Model                string                `yaml:"model"`
+	SystemPrompt         string                `yaml:"system_prompt"`
+	SystemPromptFile     string                `yaml:"system_prompt_file"`     // Relative to the configuration file.
+	SystemPromptTemplate string                `yaml:"system_prompt_template"` // text/template rendered per chat with a syndicate.PromptContext.
+	Temperature          *float32              `yaml:"temperature"`
+	Timeout              string                `yaml:"timeout"` // Duration such as "45s".
+	ResponseFormat       *ResponseFormatConfig `yaml:"response_format"`
+	Tools                []string              `yaml:"tools"` // Names of tools registered in the Registry.
+	Memory               *MemoryConfig         `yaml:"memory"`
+}
+
+// ResponseFormatConfig declares the response format of an agent.
+type ResponseFormatConfig struct {
+	Type   string         `yaml:"type"`   // json_schema (default), json_object or text.
+	Name   string         `yaml:"name"`   // Schema name; required for json_schema.
+	Schema map[string]any `yaml:"schema"` // JSON schema; required for json_schema.
+	Strict *bool          `yaml:"strict"` // Defaults to true.
+}
+
+// MemoryConfig declares the memory of an agent.
+type MemoryConfig struct {
+	Type    string            `yaml:"type"`    // Memory type registered in the Registry; defaults to simple.
+	Options map[string]string `yaml:"options"` // Settings for custom memory types.
+}
+
+// SyndicateConfig declares a syndicate of the agents in the file.
+type SyndicateConfig struct {
+	Agents      []string            `yaml:"agents"` // Agents to register; defaults to every agent in the file.
+	Pipeline    []string            `yaml:"pipeline"`
+	Handoffs    map[string][]string `yaml:"handoffs"` // Handoff targets keyed by the agent that hands off.
+	MaxHandoffs int                 `yaml:"max_handoffs"`
+}
+
+// Result holds what was built from a configuration file.
+type Result struct {
+	Agents    map[string]syndicate.Agent // Agents keyed by name.
+	Syndicate syndicate.Syndicate        // Nil when the file has no syndicate section.
+}
+
+// Option customizes how a configuration file is built.
+type Option func(*options)
+
+// options holds the settings applied with Option.
+type options struct {
+	agentOptions     []syndicate.AgentOption
+	syndicateOptions []syndicate.SyndicateOption
+}
+
+// WithAgentOptions applies additional options, such as hooks or a logger, to every agent.
+func WithAgentOptions(agentOptions ...syndicate.AgentOption) Option {
+	return func(o *options) {
+		o.agentOptions = append(o.agentOptions, agentOptions...)
+	}
+}
+
+// WithSyndicateOptions applies additional options to the syndicate.
+func WithSyndicateOptions(syndicateOptions ...syndicate.SyndicateOption) Option {
+	return func(o *options) {
+		o.syndicateOptions = append(o.syndicateOptions, syndicateOptions...)
+	}
+}
+
+// Load reads a YAML or JSON configuration file and builds its agents and syndicate.
+func Load(path string, registry *Registry, opts ...Option) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return Parse(data, path, registry, opts...)
+}
+
+// Parse builds the agents and syndicate declared in data. filename is used in error messages
+// and to resolve system prompt files relative to it.
+func Parse(data []byte, filename string, registry *Registry, opts ...Option) (*Result, error) {
+	if registry == nil {
+		return nil, errors.New("registry cannot be nil")
+	}
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	doc, file, err := decode(data, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &builder{
+		doc:      doc,
+		file:     file,
+		dir:      filepath.Dir(filename),
+		registry: registry,
+		options:  o,
+		errs:     &errorList{file: filename},
+		clients:  make(map[string]syndicate.LLMClient),
+	}
+	return b.build()
+}
+
+// decode parses data, expands environment variables and decodes the result into a File.
+func decode(data []byte, filename string) (*yaml.Node, *File, error) {
+	errs := &errorList{file: filename}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		var line int
+		message := strings.TrimPrefix(err.Error(), "yaml: ")
+		if _, scanErr := fmt.Sscanf(message, "line %d:", &line); scanErr == nil {
+			message = strings.TrimSpace(message[strings.Index(message, ":")+1:])
+		}
+		return nil, nil, Errors{{File: filename, Line: line, Message: message}}
+	}
+	if len(root.Content) == 0 {
+		return nil, nil, Errors{{File: filename, Message: "configuration is empty"}}
+	}
+	doc := root.Content[0]
+
+	expandEnv(doc, errs)
+	checkFields(doc, reflect.TypeOf(File{}), errs)
+
+	var file File
+	if err := doc.Decode(&file); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return nil, nil, fmt.Errorf("failed to decode config: %w", err)
+		}
+		errs.typeErrors(typeErr)
+	}
+	if err := errs.err(); err != nil {
+		return nil, nil, err
+	}
+	return doc, &file, nil
+}
+
+// checkFields reports mapping keys that do not correspond to a field of t.
+func checkFields(node *yaml.Node, t reflect.Type, errs *errorList) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := make(map[string]reflect.Type, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+			fields[name] = t.Field(i).Type
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fieldType, ok := fields[key.Value]
+			if !ok {
+				errs.at(key, "unknown field %q", key.Value)
+				continue
+			}
+			checkFields(value, fieldType, errs)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			checkFields(node.Content[i], t.Elem(), errs)
+		}
+	case t.Kind() == reflect.Slice && node.Kind == yaml.SequenceNode:
+		for _, item := range node.Content {
+			checkFields(item, t.Elem(), errs)
+		}
+	}
+}
+
+// lookup returns the node at path, made of mapping keys and sequence indices. When the path
+// does not exist, it returns the deepest node found, so errors still point near the problem.
+// A trailing key may be marked with a "key:" prefix to get the key node instead of its value.
+func lookup(node *yaml.Node, path ...any) *yaml.Node {
+	for _, step := range path {
+		switch step := step.(type) {
+		case string:
+			if node.Kind != yaml.MappingNode {
+				return node
+			}
+			wantKey := strings.HasPrefix(step, "key:")
+			name := strings.TrimPrefix(step, "key:")
+			found := false
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == name {
+					if wantKey {
+						node = node.Content[i]
+					} else {
+						node = node.Content[i+1]
+					}
+					found = true
+					break
+				}
+			}
+			if !found {
+				return node
+			}
+		case int:
+			if node.Kind != yaml.SequenceNode || step >= len(node.Content) {
+				return node
+			}
+			node = node.Content[step]
+		}
+	}
+	return node
+}
+
+// builder validates a decoded file and builds what it declares.
+type builder struct {
+	doc      *yaml.Node
+	file     *File
+	dir      string
+	registry *Registry
+	options  *options
+	errs     *errorList
+	clients  map[string]syndicate.LLMClient
+}
+
+// build validates the whole file, reporting every problem at once, and then builds it.
+func (b *builder) build() (*Result, error) {
+	b.buildProviders()
+
+	agentOptions := make([][]syndicate.AgentOption, len(b.file.Agents))
+	names := make(map[string]bool, len(b.file.Agents))
+	if len(b.file.Agents) == 0 {
+		b.errs.at(lookup(b.doc, "agents"), "at least one agent is required")
+	}
+	for i, cfg := range b.file.Agents {
+		if cfg.Name != "" {
+			if names[cfg.Name] {
+				b.errs.at(lookup(b.doc, "agents", i, "name"), "duplicate agent name %q", cfg.Name)
+			}
+			names[cfg.Name] = true
+		}
+		agentOptions[i] = b.agentOptions(i, cfg)
+	}
+	b.validateSyndicate(names)
+
+	if err := b.errs.err(); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Agents: make(map[string]syndicate.Agent, len(b.file.Agents))}
+	ordered := make([]syndicate.Agent, 0, len(b.file.Agents))
+	for i, cfg := range b.file.Agents {
+		agent, err := syndicate.NewAgent(append(agentOptions[i], b.options.agentOptions...)...)
+		if err != nil {
+			b.errs.at(lookup(b.doc, "agents", i), "agent %s: %v", cfg.Name, err)
+			continue
+		}
+		result.Agents[cfg.Name] = agent
+		ordered = append(ordered, agent)
+	}
+	if err := b.errs.err(); err != nil {
+		return nil, err
+	}
+
+	if b.file.Syndicate != nil {
+		syn, err := b.buildSyndicate(result.Agents, ordered)
+		if err != nil {
+			b.errs.at(lookup(b.doc, "syndicate"), "%v", err)
+			return nil, b.errs.err()
+		}
+		result.Syndicate = syn
+	}
+	return result, nil
+}
+
+// buildProviders creates one client per declared provider.
+func (b *builder) buildProviders() {
+	for _, name := range sortedKeys(b.file.Providers) {
+		cfg := b.file.Providers[name]
+		if cfg.Type == "" {
+			b.errs.at(lookup(b.doc, "providers", name), "provider %s: type is required", name)
+			continue
+		}
+		factory, ok := b.registry.providers[cfg.Type]
+		if !ok {
+			b.errs.at(lookup(b.doc, "providers", name, "type"), "provider %s: unknown type %q (known: %s)",
+				name, cfg.Type, strings.Join(sortedKeys(b.registry.providers), ", "))
+			continue
+		}
+		client, err := factory(cfg)
+		if err != nil {
+			b.errs.at(lookup(b.doc, "providers", name), "provider %s: %v", name, err)
+			continue
+		}
+		b.clients[name] = client
+	}
+}
+
+// agentOptions validates an agent entry and translates it into agent options.
+func (b *builder) agentOptions(i int, cfg AgentConfig) []syndicate.AgentOption {
+	at := func(path ...any) *yaml.Node {
+		return lookup(b.doc, append([]any{"agents", i}, path...)...)
+	}
+
+	var opts []syndicate.AgentOption
+	if cfg.Name == "" {
+		b.errs.at(at(), "agent name is required")
+	} else {
+		opts = append(opts, syndicate.WithName(cfg.Name))
+	}
+	if cfg.Description != "" {
+		opts = append(opts, syndicate.WithDescription(cfg.Description))
+	}
+
+	if cfg.Model == "" {
+		b.errs.at(at(), "agent %s: model is required", cfg.Name)
+	} else {
+		opts = append(opts, syndicate.WithModel(cfg.Model))
+	}
+
+	switch client, ok := b.clients[cfg.Provider]; {
+	case cfg.Provider == "":
+		b.errs.at(at(), "agent %s: provider is required", cfg.Name)
+	case ok:
+		opts = append(opts, syndicate.WithClient(client))
+	case !hasKey(b.file.Providers, cfg.Provider):
+		b.errs.at(at("provider"), "agent %s: unknown provider %q", cfg.Name, cfg.Provider)
+	}
+
+	if prompt := b.systemPrompt(i, cfg); prompt != nil {
+		opts = append(opts, prompt)
+	}
+
+	if cfg.Temperature != nil {
+		if *cfg.Temperature < 0 || *cfg.Temperature > 2 {
+			b.errs.at(at("temperature"), "agent %s: temperature must be between 0 and 2", cfg.Name)
+		} else {
+			opts = append(opts, syndicate.WithTemperature(*cfg.Temperature))
+		}
+	}
+
+	if cfg.Timeout != "" {
+		timeout, err := time.ParseDuration(cfg.Timeout)
+		if err != nil || timeout <= 0 {
+			b.errs.at(at("timeout"), "agent %s: invalid timeout %q", cfg.Name, cfg.Timeout)
+		} else {
+			opts = append(opts, syndicate.WithTimeout(timeout))
+		}
+	}
+
+	if cfg.ResponseFormat != nil {
+		if format := b.responseFormat(i, cfg); format != nil {
+			opts = append(opts, syndicate.WithResponseFormat(format))
+		}
+	}
+
+	for j, name := range cfg.Tools {
+		tool, ok := b.registry.tools[name]
+		if !ok {
+			b.errs.at(at("tools", j), "agent %s: unknown tool %q", cfg.Name, name)
+			continue
+		}
+		opts = append(opts, syndicate.WithTool(tool))
+	}
+
+	memory := MemoryConfig{Type: "simple"}
+	if cfg.Memory != nil {
+		memory = *cfg.Memory
+		if memory.Type == "" {
+			memory.Type = "simple"
+		}
+	}
+	factory, ok := b.registry.memories[memory.Type]
+	if !ok {
+		b.errs.at(at("memory", "type"), "agent %s: unknown memory type %q (known: %s)",
+			cfg.Name, memory.Type, strings.Join(sortedKeys(b.registry.memories), ", "))
+	} else {
+		name := cfg.Name
+		opts = append(opts, syndicate.WithMemoryFactory(func(sessionID string) (syndicate.Memory, error) {
+			return factory(name, sessionID, memory)
+		}))
+	}
+
+	return opts
+}
+
+// systemPrompt returns the option setting the agent's system prompt, or nil when there is none.
+func (b *builder) systemPrompt(i int, cfg AgentConfig) syndicate.AgentOption {
+	at := func(path ...any) *yaml.Node {
+		return lookup(b.doc, append([]any{"agents", i}, path...)...)
+	}
+
+	set := 0
+	for _, value := range []string{cfg.SystemPrompt, cfg.SystemPromptFile, cfg.SystemPromptTemplate} {
+		if value != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		b.errs.at(at(), "agent %s: only one of system_prompt, system_prompt_file and system_prompt_template may be set", cfg.Name)
+		return nil
+	}
+
+	switch {
+	case cfg.SystemPrompt != "":
+		return syndicate.WithSystemPrompt(cfg.SystemPrompt)
+
+	case cfg.SystemPromptFile != "":
+		path := cfg.SystemPromptFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(b.dir, path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			b.errs.at(at("system_prompt_file"), "agent %s: cannot read system prompt file: %v", cfg.Name, err)
+			return nil
+		}
+		return syndicate.WithSystemPrompt(string(data))
+
+	case cfg.SystemPromptTemplate != "":
+		tmpl, err := template.New(cfg.Name).Option("missingkey=error").Parse(cfg.SystemPromptTemplate)
+		if err != nil {
+			b.errs.at(at("system_prompt_template"), "agent %s: invalid system prompt template: %v", cfg.Name, err)
+			return nil
+		}
+		return syndicate.WithSystemPromptProvider(func(ctx context.Context, pc syndicate.PromptContext) (string, error) {
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, pc); err != nil {
+				return "", err
+			}
+			return buf.String(), nil
+		})
+	}
+	return nil
+}
+
+// responseFormat validates and builds the response format of an agent.
+func (b *builder) responseFormat(i int, cfg AgentConfig) *syndicate.ResponseFormat {
+	at := func(path ...any) *yaml.Node {
+		return lookup(b.doc, append([]any{"agents", i, "response_format"}, path...)...)
+	}
+	rf := cfg.ResponseFormat
+
+	switch rf.Type {
+	case "text", "json_object":
+		return &syndicate.ResponseFormat{Type: rf.Type}
+	case "", "json_schema":
+	default:
+		b.errs.at(at("type"), "agent %s: unknown response format type %q", cfg.Name, rf.Type)
+		return nil
+	}
+
+	if rf.Name == "" {
+		b.errs.at(at(), "agent %s: response format name is required", cfg.Name)
+	}
+	if len(rf.Schema) == 0 {
+		b.errs.at(at(), "agent %s: response format schema is required", cfg.Name)
+		return nil
+	}
+	schema, err := json.Marshal(rf.Schema)
+	if err != nil {
+		b.errs.at(at("schema"), "agent %s: invalid response format schema: %v", cfg.Name, err)
+		return nil
+	}
+
+	strict := true
+	if rf.Strict != nil {
+		strict = *rf.Strict
+	}
+	return &syndicate.ResponseFormat{
+		Type: "json_schema",
+		JSONSchema: &syndicate.JSONSchema{
+			Name:   rf.Name,
+			Schema: schema,
+			Strict: strict,
+		},
+	}
+}
+
+// validateSyndicate checks that the syndicate section only references declared agents.
+func (b *builder) validateSyndicate(names map[string]bool) {
+	cfg := b.file.Syndicate
+	if cfg == nil {
+		return
+	}
+
+	members := names
+	if len(cfg.Agents) > 0 {
+		members = make(map[string]bool, len(cfg.Agents))
+		for i, name := range cfg.Agents {
+			if !names[name] {
+				b.errs.at(lookup(b.doc, "syndicate", "agents", i), "unknown agent %q", name)
+			}
+			members[name] = true
+		}
+	}
+	for i, name := range cfg.Pipeline {
+		if !members[name] {
+			b.errs.at(lookup(b.doc, "syndicate", "pipeline", i), "pipeline agent %q is not in the syndicate", name)
+		}
+	}
+	for _, from := range sortedKeys(cfg.Handoffs) {
+		if !members[from] {
+			b.errs.at(lookup(b.doc, "syndicate", "handoffs", "key:"+from), "handoff agent %q is not in the syndicate", from)
+		}
+		for i, to := range cfg.Handoffs[from] {
+			if !members[to] {
+				b.errs.at(lookup(b.doc, "syndicate", "handoffs", from, i), "handoff target %q is not in the syndicate", to)
+			}
+		}
+	}
+	if cfg.MaxHandoffs < 0 {
+		b.errs.at(lookup(b.doc, "syndicate", "max_handoffs"), "max_handoffs cannot be negative")
+	}
+}
+
+// buildSyndicate creates the syndicate declared in the file from the built agents.
+func (b *builder) buildSyndicate(agents map[string]syndicate.Agent, ordered []syndicate.Agent) (syndicate.Syndicate, error) {
+	cfg := b.file.Syndicate
+
+	members := ordered
+	if len(cfg.Agents) > 0 {
+		members = make([]syndicate.Agent, 0, len(cfg.Agents))
+		for _, name := range cfg.Agents {
+			members = append(members, agents[name])
+		}
+	}
+
+	opts := []syndicate.SyndicateOption{syndicate.WithAgents(members...)}
+	if len(cfg.Pipeline) > 0 {
+		opts = append(opts, syndicate.WithPipeline(cfg.Pipeline...))
+	}
+	for _, from := range sortedKeys(cfg.Handoffs) {
+		opts = append(opts, syndicate.WithHandoffs(from, cfg.Handoffs[from]...))
+	}
+	if cfg.MaxHandoffs > 0 {
+		opts = append(opts, syndicate.WithMaxHandoffs(cfg.MaxHandoffs))
+	}
+	opts = append(opts, b.options.syndicateOptions...)
+
+	return syndicate.NewSyndicate(opts...)
+}
+
+// hasKey reports whether m contains key.
+func hasKey[V any](m map[string]V, key string) bool {
+	_, ok := m[key]
+	return ok
+}
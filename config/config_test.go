@@ -0,0 +1,237 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	syndicate "github.com/Dieg0Code/syndicate-go"
+)
+
+// fakeClient registra los requests recibidos y responde siempre con el mismo texto.
+type fakeClient struct {
+	mu       sync.Mutex
+	apiKey   string
+	requests []syndicate.ChatCompletionRequest
+}
+
+func (c *fakeClient) CreateChatCompletion(ctx context.Context, req syndicate.ChatCompletionRequest) (syndicate.ChatCompletionResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	return syndicate.ChatCompletionResponse{
+		Choices: []syndicate.Choice{{
+			Message:      syndicate.Message{Role: syndicate.RoleAssistant, Content: "ok"},
+			FinishReason: syndicate.FinishReasonStop,
+		}},
+	}, nil
+}
+
+// fakeTool es una herramienta mínima para registrar en el registro.
+type fakeTool struct{ name string }
+
+func (t fakeTool) GetDefinition() syndicate.ToolDefinition {
+	return syndicate.ToolDefinition{Name: t.name, Description: "test tool", Parameters: json.RawMessage(`{"type":"object"}`)}
+}
+
+func (t fakeTool) Execute(args json.RawMessage) (interface{}, error) { return "ok", nil }
+
+// newTestRegistry crea un registro con un proveedor falso y una herramienta.
+func newTestRegistry(t *testing.T) (*Registry, map[string]*fakeClient) {
+	t.Helper()
+	clients := make(map[string]*fakeClient)
+	registry := NewRegistry()
+	if err := registry.RegisterProvider("fake", func(cfg ProviderConfig) (syndicate.LLMClient, error) {
+		client := &fakeClient{apiKey: cfg.APIKey}
+		clients[cfg.APIKey] = client
+		return client, nil
+	}); err != nil {
+		t.Fatalf("error registrando proveedor: %v", err)
+	}
+	if err := registry.RegisterTool(fakeTool{name: "lookup_order"}); err != nil {
+		t.Fatalf("error registrando herramienta: %v", err)
+	}
+	return registry, clients
+}
+
+// TestLoad verifica que se construyan agentes y syndicate desde un archivo YAML completo.
+func TestLoad(t *testing.T) {
+	t.Setenv("TEST_CONFIG_KEY", "secret")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "triage.md"), []byte("Eres el agente de triaje."), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "agents.yaml")
+	content := `
+providers:
+  main:
+    type: fake
+    api_key: ${TEST_CONFIG_KEY}
+agents:
+  - name: triage
+    description: Routes requests
+    provider: main
+    model: gpt-4o
+    system_prompt_file: triage.md
+    tools: [lookup_order]
+    timeout: 45s
+  - name: billing
+    provider: main
+    model: ${TEST_CONFIG_MODEL:-gpt-4o-mini}
+    system_prompt_template: "Ayudas a {{.UserName}} con facturas."
+    temperature: 0.2
+    response_format:
+      name: invoice
+      schema:
+        type: object
+        properties:
+          total: {type: number}
+        required: [total]
+        additionalProperties: false
+syndicate:
+  pipeline: [triage, billing]
+  handoffs:
+    triage: [billing]
+  max_handoffs: 3
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	registry, clients := newTestRegistry(t)
+	result, err := Load(path, registry)
+	if err != nil {
+		t.Fatalf("error cargando configuración: %v", err)
+	}
+	if len(result.Agents) != 2 || result.Syndicate == nil {
+		t.Fatalf("resultado inesperado: %+v", result)
+	}
+	if got := result.Syndicate.GetPipeline(); strings.Join(got, ",") != "triage,billing" {
+		t.Errorf("pipeline inesperado: %v", got)
+	}
+
+	client := clients["secret"]
+	if client == nil {
+		t.Fatal("el proveedor no recibió la clave del entorno")
+	}
+
+	if _, err := result.Agents["triage"].Chat(context.Background(), syndicate.WithUserName("ana"), syndicate.WithInput("hola")); err != nil {
+		t.Fatalf("error en chat: %v", err)
+	}
+	req := client.requests[0]
+	if req.Messages[0].Content != "Eres el agente de triaje." || len(req.Tools) != 1 || req.Tools[0].Name != "lookup_order" {
+		t.Errorf("request de triage inesperado: %+v", req)
+	}
+
+	if _, err := result.Agents["billing"].Chat(context.Background(), syndicate.WithUserName("ana"), syndicate.WithInput("mi factura")); err != nil {
+		t.Fatalf("error en chat: %v", err)
+	}
+	req = client.requests[1]
+	if req.Model != "gpt-4o-mini" || req.Temperature != 0.2 {
+		t.Errorf("modelo o temperatura inesperados: %s %v", req.Model, req.Temperature)
+	}
+	if req.Messages[0].Content != "Ayudas a ana con facturas." {
+		t.Errorf("plantilla mal renderizada: %s", req.Messages[0].Content)
+	}
+	if req.ResponseFormat == nil || req.ResponseFormat.JSONSchema.Name != "invoice" || !req.ResponseFormat.JSONSchema.Strict ||
+		!strings.Contains(string(req.ResponseFormat.JSONSchema.Schema), `"total"`) {
+		t.Errorf("formato de respuesta inesperado: %+v", req.ResponseFormat)
+	}
+}
+
+// TestParseJSON verifica que también se acepten archivos JSON.
+func TestParseJSON(t *testing.T) {
+	registry, _ := newTestRegistry(t)
+	data := `{"providers": {"main": {"type": "fake", "api_key": "k"}},
+	          "agents": [{"name": "solo", "provider": "main", "model": "gpt-4o", "system_prompt": "hola"}]}`
+
+	result, err := Parse([]byte(data), "agents.json", registry)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if result.Agents["solo"] == nil || result.Syndicate != nil {
+		t.Errorf("resultado inesperado: %+v", result)
+	}
+}
+
+// TestParseValidationErrors verifica que se informen todos los errores con su posición.
+func TestParseValidationErrors(t *testing.T) {
+	registry, _ := newTestRegistry(t)
+	data := `providers:
+  main:
+    type: fake
+    api_key: k
+  other:
+    type: nope
+agents:
+  - name: a
+    provider: missing
+    model: gpt-4o
+    temperature: 3
+    tools: [unknown_tool]
+    colour: red
+  - name: a
+    provider: main
+    system_prompt: x
+    system_prompt_template: y
+syndicate:
+  pipeline: [a, ghost]
+`
+	_, err := Parse([]byte(data), "agents.yaml", registry)
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("se esperaban Errors, se obtuvo: %v", err)
+	}
+
+	expected := []string{
+		`agents.yaml:13:5: unknown field "colour"`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("falta el error %q en:\n%s", want, err)
+		}
+	}
+
+	// Los errores de campos desconocidos se informan antes de validar el resto.
+	data = strings.Replace(data, "    colour: red\n", "", 1)
+	_, err = Parse([]byte(data), "agents.yaml", registry)
+	expected = []string{
+		`agents.yaml:6:11: provider other: unknown type "nope"`,
+		`agents.yaml:9:15: agent a: unknown provider "missing"`,
+		`agents.yaml:11:18: agent a: temperature must be between 0 and 2`,
+		`agents.yaml:12:13: agent a: unknown tool "unknown_tool"`,
+		`agents.yaml:13:11: duplicate agent name "a"`,
+		`agents.yaml:13:5: agent a: model is required`,
+		`agents.yaml:13:5: agent a: only one of system_prompt`,
+		`agents.yaml:18:17: pipeline agent "ghost" is not in the syndicate`,
+	}
+	for _, want := range expected {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("falta el error %q en:\n%s", want, err)
+		}
+	}
+}
+
+// TestParseTypeAndSyntaxErrors verifica los errores de tipo y de sintaxis.
+func TestParseTypeAndSyntaxErrors(t *testing.T) {
+	registry, _ := newTestRegistry(t)
+
+	_, err := Parse([]byte("agents:\n  - name: a\n    temperature: warm\n"), "a.yaml", registry)
+	if err == nil || !strings.Contains(err.Error(), "a.yaml:3:") {
+		t.Errorf("se esperaba un error de tipo con línea, se obtuvo: %v", err)
+	}
+
+	_, err = Parse([]byte("agents: [\n"), "b.yaml", registry)
+	if err == nil || !strings.HasPrefix(err.Error(), "b.yaml:") {
+		t.Errorf("se esperaba un error de sintaxis, se obtuvo: %v", err)
+	}
+
+	if _, err := Parse([]byte(""), "c.yaml", registry); err == nil {
+		t.Error("se esperaba un error con un archivo vacío")
+	}
+}
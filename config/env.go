@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envPattern matches ${VAR} and ${VAR:-default} references, and their $${...} escapes.
+var envPattern = regexp.MustCompile(`\$?\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// literalKeys are the keys whose values are never expanded: prompts, which commonly contain
+// placeholders of their own.
+var literalKeys = map[string]bool{
+	"system_prompt":          true,
+	"system_prompt_template": true,
+}
+
+// expandEnv replaces environment variable references in the scalar values of the document,
+// except the values of literalKeys. A reference written as $${VAR} is kept literally as ${VAR}.
+// References to unset variables without a default are reported as errors.
+func expandEnv(node *yaml.Node, errs *errorList) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		expandScalar(node, errs)
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if !literalKeys[node.Content[i].Value] {
+				expandEnv(node.Content[i+1], errs)
+			}
+		}
+	default:
+		for _, child := range node.Content {
+			expandEnv(child, errs)
+		}
+	}
+}
+
+// expandScalar replaces the environment variable references of a scalar value.
+func expandScalar(node *yaml.Node, errs *errorList) {
+	if !strings.Contains(node.Value, "${") {
+		return
+	}
+	expanded := envPattern.ReplaceAllStringFunc(node.Value, func(ref string) string {
+		if strings.HasPrefix(ref, "$$") {
+			return ref[1:]
+		}
+		match := envPattern.FindStringSubmatch(ref)
+		if value, ok := os.LookupEnv(match[1]); ok {
+			return value
+		}
+		if match[2] != "" {
+			return match[3]
+		}
+		errs.at(node, "environment variable %s is not set", match[1])
+		return ""
+	})
+	if expanded != node.Value {
+		node.Value = expanded
+		// Let plain values be resolved again, so "${PORT}" can fill a number.
+		if node.Style == 0 {
+			node.Tag = ""
+		}
+	}
+}
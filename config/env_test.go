@@ -0,0 +1,73 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestExpandEnv verifica la sustitución de variables de entorno, los valores por defecto y los errores.
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("TEST_ENV_NAME", "mundo")
+	t.Setenv("TEST_ENV_PORT", "8080")
+
+	var root yaml.Node
+	data := "greeting: hola ${TEST_ENV_NAME}\nport: ${TEST_ENV_PORT}\nquoted: \"${TEST_ENV_PORT}\"\nfallback: ${TEST_ENV_UNSET_X:-valor}\nmissing: ${TEST_ENV_UNSET_Y}\n"
+	if err := yaml.Unmarshal([]byte(data), &root); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := &errorList{file: "env.yaml"}
+	expandEnv(root.Content[0], errs)
+
+	var values struct {
+		Greeting string `yaml:"greeting"`
+		Port     int    `yaml:"port"`
+		Quoted   string `yaml:"quoted"`
+		Fallback string `yaml:"fallback"`
+	}
+	if err := root.Content[0].Decode(&values); err != nil {
+		t.Fatalf("error decodificando: %v", err)
+	}
+	if values.Greeting != "hola mundo" || values.Port != 8080 || values.Quoted != "8080" || values.Fallback != "valor" {
+		t.Errorf("valores inesperados: %+v", values)
+	}
+
+	if len(errs.errors) != 1 || !strings.Contains(errs.errors[0].Error(), "env.yaml:5:10: environment variable TEST_ENV_UNSET_Y is not set") {
+		t.Errorf("errores inesperados: %v", errs.err())
+	}
+}
+
+// TestExpandEnvLiterals verifica que los prompts no se expandan y que $${VAR} se mantenga literal.
+func TestExpandEnvLiterals(t *testing.T) {
+	t.Setenv("TEST_ENV_KEY", "secreto")
+
+	var root yaml.Node
+	data := "api_key: ${TEST_ENV_KEY}\nescaped: costo $${TEST_ENV_KEY}\nagents:\n  - system_prompt: Hola ${name}\n    system_prompt_template: \"{{.Input}} ${user}\"\n"
+	if err := yaml.Unmarshal([]byte(data), &root); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := &errorList{file: "env.yaml"}
+	expandEnv(root.Content[0], errs)
+	if err := errs.err(); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	var values struct {
+		APIKey  string `yaml:"api_key"`
+		Escaped string `yaml:"escaped"`
+		Agents  []struct {
+			SystemPrompt         string `yaml:"system_prompt"`
+			SystemPromptTemplate string `yaml:"system_prompt_template"`
+		} `yaml:"agents"`
+	}
+	if err := root.Content[0].Decode(&values); err != nil {
+		t.Fatalf("error decodificando: %v", err)
+	}
+	if values.APIKey != "secreto" || values.Escaped != "costo ${TEST_ENV_KEY}" ||
+		values.Agents[0].SystemPrompt != "Hola ${name}" || values.Agents[0].SystemPromptTemplate != "{{.Input}} ${user}" {
+		t.Errorf("valores inesperados: %+v", values)
+	}
+}
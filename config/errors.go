@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Error is a problem found in a configuration file, with the position it refers to.
+// Line and Column are 1-based; they are zero when the position is unknown.
+type Error struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+	case e.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+	default:
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	}
+}
+
+// Errors lists every problem found in a configuration file, in the order they were found.
+type Errors []*Error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// errorList collects positioned errors while a file is processed.
+type errorList struct {
+	file   string
+	errors Errors
+}
+
+// at records an error at the position of node.
+func (l *errorList) at(node *yaml.Node, format string, args ...any) {
+	err := &Error{File: l.file, Message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		err.Line, err.Column = node.Line, node.Column
+	}
+	l.errors = append(l.errors, err)
+}
+
+// err returns the collected errors, or nil when there are none.
+func (l *errorList) err() error {
+	if len(l.errors) == 0 {
+		return nil
+	}
+	return l.errors
+}
+
+// typeErrors converts the errors reported by the YAML decoder, which only carry a line number.
+func (l *errorList) typeErrors(err *yaml.TypeError) {
+	for _, message := range err.Errors {
+		var line int
+		if _, scanErr := fmt.Sscanf(message, "line %d:", &line); scanErr == nil {
+			message = strings.TrimSpace(message[strings.Index(message, ":")+1:])
+		}
+		l.errors = append(l.errors, &Error{File: l.file, Line: line, Message: message})
+	}
+}
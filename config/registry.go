@@ -0,0 +1,112 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	syndicate "github.com/Dieg0Code/syndicate-go"
+)
+
+// ProviderFactory creates an LLM client from a provider entry of a configuration file.
+type ProviderFactory func(cfg ProviderConfig) (syndicate.LLMClient, error)
+
+// MemoryFactory creates the memory of an agent's conversation session from a memory entry
+// of a configuration file. The default session has an empty session ID.
+type MemoryFactory func(agentName, sessionID string, cfg MemoryConfig) (syndicate.Memory, error)
+
+// Registry resolves the names used in configuration files: provider types, tools and memory types.
+// NewRegistry registers the built-in providers (openai, azure and deepseek) and the simple memory.
+type Registry struct {
+	providers map[string]ProviderFactory
+	tools     map[string]syndicate.Tool
+	memories  map[string]MemoryFactory
+}
+
+// NewRegistry creates a Registry with the built-in provider and memory types.
+func NewRegistry() *Registry {
+	r := &Registry{
+		providers: make(map[string]ProviderFactory),
+		tools:     make(map[string]syndicate.Tool),
+		memories:  make(map[string]MemoryFactory),
+	}
+
+	r.providers["openai"] = func(cfg ProviderConfig) (syndicate.LLMClient, error) {
+		if cfg.APIKey == "" {
+			return nil, errors.New("api_key is required")
+		}
+		return syndicate.NewOpenAIClient(cfg.APIKey), nil
+	}
+	r.providers["azure"] = func(cfg ProviderConfig) (syndicate.LLMClient, error) {
+		if cfg.APIKey == "" {
+			return nil, errors.New("api_key is required")
+		}
+		return syndicate.NewOpenAIAzureClient(cfg.APIKey), nil
+	}
+	r.providers["deepseek"] = func(cfg ProviderConfig) (syndicate.LLMClient, error) {
+		if cfg.APIKey == "" {
+			return nil, errors.New("api_key is required")
+		}
+		if cfg.BaseURL == "" {
+			return nil, errors.New("base_url is required")
+		}
+		return syndicate.NewDeepseekR1Client(cfg.APIKey, cfg.BaseURL), nil
+	}
+	r.memories["simple"] = func(agentName, sessionID string, cfg MemoryConfig) (syndicate.Memory, error) {
+		return syndicate.NewSimpleMemory(), nil
+	}
+
+	return r
+}
+
+// RegisterProvider registers a provider type, replacing any provider with the same type.
+func (r *Registry) RegisterProvider(providerType string, factory ProviderFactory) error {
+	if providerType == "" {
+		return errors.New("provider type cannot be empty")
+	}
+	if factory == nil {
+		return errors.New("provider factory cannot be nil")
+	}
+	r.providers[providerType] = factory
+	return nil
+}
+
+// RegisterTool registers tools under the names of their definitions.
+func (r *Registry) RegisterTool(tools ...syndicate.Tool) error {
+	for _, tool := range tools {
+		if tool == nil {
+			return errors.New("tool cannot be nil")
+		}
+		name := tool.GetDefinition().Name
+		if name == "" {
+			return errors.New("tool name cannot be empty")
+		}
+		if _, exists := r.tools[name]; exists {
+			return fmt.Errorf("tool %s is already registered", name)
+		}
+		r.tools[name] = tool
+	}
+	return nil
+}
+
+// RegisterMemory registers a memory type, replacing any memory with the same type.
+func (r *Registry) RegisterMemory(memoryType string, factory MemoryFactory) error {
+	if memoryType == "" {
+		return errors.New("memory type cannot be empty")
+	}
+	if factory == nil {
+		return errors.New("memory factory cannot be nil")
+	}
+	r.memories[memoryType] = factory
+	return nil
+}
+
+// sortedKeys returns the keys of a map in alphabetical order, for stable error messages.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,76 @@
+package syndicate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// continuationPrompt is sent after a truncated answer to ask the model to carry on.
+const continuationPrompt = "Your previous answer was cut off. Continue exactly where you left off, without repeating anything."
+
+// ErrTruncated is matched (with errors.Is) by the TruncatedError returned when an answer
+// is still cut off by the length limit after the allowed continuations.
+var ErrTruncated = errors.New("response truncated by length limit")
+
+// TruncatedError reports an answer that ended with FinishReasonLength.
+// Partial holds the text generated so far, including any stitched continuations.
+type TruncatedError struct {
+	Partial       string
+	Continuations int
+}
+
+func (e *TruncatedError) Error() string {
+	return fmt.Sprintf("%s after %d continuation(s)", ErrTruncated.Error(), e.Continuations)
+}
+
+// Is makes errors.Is(err, ErrTruncated) report true.
+func (e *TruncatedError) Is(target error) bool {
+	return target == ErrTruncated
+}
+
+// WithAutoContinue makes the agent handle answers truncated by the length limit.
+// The partial answer is sent back to the model, which is asked to continue, up to maxContinuations
+// times; the pieces are stitched into a single answer. If the answer is still truncated after that,
+// Chat returns a *TruncatedError carrying the partial output. With maxContinuations set to 0 no
+// continuation is attempted and the error is returned right away, so callers can decide what to do.
+//
+// Without this option, a truncated answer is stored and returned as if it were complete.
+func WithAutoContinue(maxContinuations int) AgentOption {
+	return func(a *agent) error {
+		if maxContinuations < 0 {
+			return errors.New("max continuations cannot be negative")
+		}
+		a.continueOnLength = true
+		a.maxContinuations = maxContinuations
+		return nil
+	}
+}
+
+// continueTruncated asks the model to continue a truncated answer until it finishes or the
+// continuation budget runs out. Continuation requests are not stored in memory.
+func (a *agent) continueTruncated(ctx context.Context, t *turn, messages []Message, partial string) (string, error) {
+	for {
+		if t.result.Continuations >= a.maxContinuations {
+			return "", &TruncatedError{Partial: partial, Continuations: t.result.Continuations}
+		}
+		t.result.Continuations++
+
+		continuation := make([]Message, 0, len(messages)+2)
+		continuation = append(continuation, messages...)
+		continuation = append(continuation,
+			Message{Role: RoleAssistant, Content: partial, Name: a.name},
+			Message{Role: RoleUser, Content: continuationPrompt},
+		)
+
+		choice, err := a.runRound(ctx, t, continuation)
+		if err != nil {
+			return "", err
+		}
+		partial += choice.Message.Content
+
+		if choice.FinishReason != FinishReasonLength {
+			return partial, nil
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package syndicate
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// truncatedResponse construye una respuesta cortada por el límite de longitud.
+func truncatedResponse(content string) ChatCompletionResponse {
+	return ChatCompletionResponse{
+		Choices: []Choice{{Message: Message{Role: RoleAssistant, Content: content}, FinishReason: FinishReasonLength}},
+	}
+}
+
+// TestAutoContinueStitchesPieces verifica que las continuaciones se unan en una sola respuesta.
+func TestAutoContinueStitchesPieces(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		truncatedResponse("Había una "),
+		truncatedResponse("vez un "),
+		textResponse("agente."),
+	}}
+	mem := NewSimpleMemory()
+	agent, _ := NewAgent(
+		WithClient(client),
+		WithName("writer"),
+		WithMemory(mem),
+		WithModel("gpt-4o"),
+		WithAutoContinue(3),
+	)
+
+	result, err := agent.(DetailedAgent).ChatDetailed(context.Background(), WithUserName("user"), WithInput("cuento"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if result.Content != "Había una vez un agente." {
+		t.Errorf("respuesta unida inesperada: %q", result.Content)
+	}
+	if result.Continuations != 2 || result.Rounds != 3 {
+		t.Errorf("se esperaban 2 continuaciones y 3 rondas, se obtuvo %d y %d", result.Continuations, result.Rounds)
+	}
+
+	last := client.Requests()[2].Messages
+	if last[len(last)-2].Content != "Había una vez un " || last[len(last)-1].Content != continuationPrompt {
+		t.Errorf("la continuación no envió el texto parcial y la instrucción: %+v", last[len(last)-2:])
+	}
+
+	msgs := mem.Get()
+	if len(msgs) != 2 || msgs[1].Content != "Había una vez un agente." {
+		t.Errorf("la memoria solo debería contener la pregunta y la respuesta completa: %+v", msgs)
+	}
+}
+
+// TestAutoContinueExhausted verifica que se retorne ErrTruncated con el texto parcial al agotar las continuaciones.
+func TestAutoContinueExhausted(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		truncatedResponse("uno "),
+		truncatedResponse("dos "),
+	}}
+	agent, _ := NewAgent(
+		WithClient(client),
+		WithName("writer"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithAutoContinue(1),
+	)
+
+	_, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("cuento"))
+	if !errors.Is(err, ErrTruncated) {
+		t.Fatalf("se esperaba ErrTruncated, se obtuvo: %v", err)
+	}
+	var truncated *TruncatedError
+	if !errors.As(err, &truncated) || truncated.Partial != "uno dos " || truncated.Continuations != 1 {
+		t.Errorf("TruncatedError inesperado: %+v", truncated)
+	}
+}
+
+// TestAutoContinueZeroReportsTruncation verifica que con 0 continuaciones se reporte el truncamiento sin reintentar.
+func TestAutoContinueZeroReportsTruncation(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{truncatedResponse("parcial")}}
+	agent, _ := NewAgent(
+		WithClient(client),
+		WithName("writer"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithAutoContinue(0),
+	)
+
+	_, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("cuento"))
+	var truncated *TruncatedError
+	if !errors.As(err, &truncated) || truncated.Partial != "parcial" {
+		t.Errorf("se esperaba TruncatedError con el texto parcial, se obtuvo: %v", err)
+	}
+	if len(client.Requests()) != 1 {
+		t.Errorf("no debería enviarse ninguna continuación, se enviaron %d requests", len(client.Requests()))
+	}
+
+	if _, err := NewAgent(WithAutoContinue(-1)); err == nil {
+		t.Error("se esperaba error por continuaciones negativas")
+	}
+}
+
+// TestTruncationWithoutOption verifica que sin la opción se mantenga el comportamiento original.
+func TestTruncationWithoutOption(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{truncatedResponse("parcial")}}
+	agent, _ := NewAgent(
+		WithClient(client),
+		WithName("writer"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+	)
+
+	result, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("cuento"))
+	if err != nil || result != "parcial" {
+		t.Errorf("se esperaba la respuesta truncada sin error, se obtuvo %q, %v", result, err)
+	}
+}
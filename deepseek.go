@@ -3,7 +3,9 @@ package syndicate
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
+	"time"
 
 	deepseek "github.com/cohesion-org/deepseek-go"
 )
@@ -11,13 +13,15 @@ import (
 // DeepseekR1Client implementa LLMClient usando el SDK de DeepseekR1.
 type DeepseekR1Client struct {
 	client *deepseek.Client
+	logger *slog.Logger
 }
 
 // NewDeepseekR1Client crea un nuevo cliente para DeepseekR1.
 // Recibe la API key y el baseURL (por ejemplo, "https://models.inference.ai.azure.com/").
-func NewDeepseekR1Client(apiKey, baseURL string) LLMClient {
+func NewDeepseekR1Client(apiKey, baseURL string, options ...ClientOption) LLMClient {
 	return &DeepseekR1Client{
 		client: deepseek.NewClient(apiKey, baseURL),
+		logger: newClientConfig(options).logger,
 	}
 }
 
@@ -51,9 +55,10 @@ func mapFromDeepseekResponse(resp *deepseek.ChatCompletionResponse) ChatCompleti
 		})
 	}
 	usage := Usage{
-		PromptTokens:     resp.Usage.PromptTokens,
-		CompletionTokens: resp.Usage.CompletionTokens,
-		TotalTokens:      resp.Usage.TotalTokens,
+		PromptTokens:       resp.Usage.PromptTokens,
+		CompletionTokens:   resp.Usage.CompletionTokens,
+		TotalTokens:        resp.Usage.TotalTokens,
+		CachedPromptTokens: resp.Usage.PromptCacheHitTokens,
 	}
 	return ChatCompletionResponse{
 		Choices: choices,
@@ -70,10 +75,25 @@ func (d *DeepseekR1Client) CreateChatCompletion(ctx context.Context, req ChatCom
 		// DeepseekR1 no soporta tools ni parámetros como Temperature o ResponseFormat.
 	}
 
+	logger := loggerOrDiscard(d.logger)
+	start := time.Now()
 	resp, err := d.client.CreateChatCompletion(ctx, deepseekReq)
 	if err != nil {
+		logger.ErrorContext(ctx, "deepseek chat completion failed",
+			slog.String("model", req.Model),
+			slog.Duration("latency", time.Since(start)),
+			slog.Any("error", err),
+		)
 		return ChatCompletionResponse{}, fmt.Errorf("deepseek error: %w", err)
 	}
 
-	return mapFromDeepseekResponse(resp), nil
+	result := mapFromDeepseekResponse(resp)
+	logger.DebugContext(ctx, "deepseek chat completion",
+		append([]any{
+			slog.String("model", req.Model),
+			slog.Int("messages", len(req.Messages)),
+			slog.Duration("latency", time.Since(start)),
+		}, usageLogAttrs(result.Usage)...)...,
+	)
+	return result, nil
 }
@@ -59,9 +59,10 @@ func TestMapFromDeepseekResponse(t *testing.T) {
 			},
 		},
 		Usage: deepseek.Usage{
-			PromptTokens:     15,
-			CompletionTokens: 25,
-			TotalTokens:      40,
+			PromptTokens:         15,
+			CompletionTokens:     25,
+			TotalTokens:          40,
+			PromptCacheHitTokens: 12,
 		},
 	}
 
@@ -82,7 +83,8 @@ func TestMapFromDeepseekResponse(t *testing.T) {
 	}
 	if internalResp.Usage.PromptTokens != 15 ||
 		internalResp.Usage.CompletionTokens != 25 ||
-		internalResp.Usage.TotalTokens != 40 {
+		internalResp.Usage.TotalTokens != 40 ||
+		internalResp.Usage.CachedPromptTokens != 12 {
 		t.Errorf("uso inesperado: %+v", internalResp.Usage)
 	}
 }
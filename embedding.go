@@ -4,15 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	openai "github.com/sashabaranov/go-openai"
 )
 
+// EmbeddingFunc returns the embedding vector of a text.
+type EmbeddingFunc func(ctx context.Context, text string) ([]float32, error)
+
 // Embedder is responsible for generating embeddings using the OpenAI API.
 type Embedder struct {
 	client *openai.Client        // OpenAI client to perform API calls.
 	model  openai.EmbeddingModel // Default embedding model to use.
+	logger *slog.Logger          // Structured logger; discards records when nil.
 }
 
 // GenerateEmbedding generates an embedding for the provided data string.
@@ -42,11 +47,23 @@ func (e *Embedder) GenerateEmbedding(ctx context.Context, data string, model ...
 	}
 
 	// Call the OpenAI API to generate embeddings.
+	logger := loggerOrDiscard(e.logger)
+	start := time.Now()
 	res, err := e.client.CreateEmbeddings(ctx, req)
 	if err != nil {
-		log.Printf("failed to create embeddings for model '%s': %v", modelName, err)
+		logger.ErrorContext(ctx, "failed to create embeddings",
+			slog.String("model", string(modelName)),
+			slog.Duration("latency", time.Since(start)),
+			slog.Any("error", err),
+		)
 		return nil, fmt.Errorf("create embeddings error: %w", err)
 	}
+	logger.DebugContext(ctx, "embeddings created",
+		slog.String("model", string(modelName)),
+		slog.Int("input_length", len(data)),
+		slog.Duration("latency", time.Since(start)),
+		slog.Int("total_tokens", res.Usage.TotalTokens),
+	)
 
 	// Validate that embedding data was returned.
 	if len(res.Data) == 0 {
@@ -57,10 +74,18 @@ func (e *Embedder) GenerateEmbedding(ctx context.Context, data string, model ...
 	return res.Data[0].Embedding, nil
 }
 
+// EmbeddingFunc returns a function that embeds text with the Embedder's default model.
+func (e *Embedder) EmbeddingFunc() EmbeddingFunc {
+	return func(ctx context.Context, text string) ([]float32, error) {
+		return e.GenerateEmbedding(ctx, text)
+	}
+}
+
 // EmbedderBuilder provides a fluent API to configure and build an Embedder instance.
 type EmbedderBuilder struct {
 	client *openai.Client        // OpenAI client to be used by the Embedder.
 	model  openai.EmbeddingModel // Embedding model to be used; defaults to a preset model.
+	logger *slog.Logger          // Structured logger to be used by the Embedder.
 }
 
 // NewEmbedderBuilder initializes a new EmbedderBuilder with default settings.
@@ -83,6 +108,12 @@ func (b *EmbedderBuilder) SetModel(model openai.EmbeddingModel) *EmbedderBuilder
 	return b
 }
 
+// SetLogger configures the structured logger used by the Embedder.
+func (b *EmbedderBuilder) SetLogger(logger *slog.Logger) *EmbedderBuilder {
+	b.logger = logger
+	return b
+}
+
 // Build constructs the Embedder instance based on the current configuration.
 // Returns an error if the required OpenAI client is not configured.
 func (b *EmbedderBuilder) Build() (*Embedder, error) {
@@ -92,5 +123,6 @@ func (b *EmbedderBuilder) Build() (*Embedder, error) {
 	return &Embedder{
 		client: b.client,
 		model:  b.model,
+		logger: loggerOrDiscard(b.logger),
 	}, nil
 }
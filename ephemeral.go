@@ -0,0 +1,58 @@
+package syndicate
+
+import (
+	"errors"
+	"sync"
+)
+
+// errDryRun stops a dry-run turn once its first request has been built.
+var errDryRun = errors.New("dry run")
+
+// WithEphemeral runs the chat without changing the session's memory: the history is read as usual,
+// but the user message, tool messages and answer are kept only for the duration of the turn.
+// It is meant for one-off questions such as classifications or previews.
+func WithEphemeral() ChatOption {
+	return func(r *chatRequest) {
+		r.ephemeral = true
+	}
+}
+
+// WithDryRun builds the first request the chat would send, after running the BeforeRequest hooks,
+// and returns it in ChatResult.Request without calling the provider. Nothing is written to memory
+// and Chat returns an empty answer.
+func WithDryRun() ChatOption {
+	return func(r *chatRequest) {
+		r.ephemeral = true
+		r.dryRun = true
+	}
+}
+
+// overlayMemory reads through to a base memory and keeps its own writes apart from it.
+type overlayMemory struct {
+	base    Memory
+	mutex   sync.RWMutex
+	pending []Message
+}
+
+// newOverlayMemory creates an overlay over base.
+func newOverlayMemory(base Memory) *overlayMemory {
+	return &overlayMemory{base: base}
+}
+
+// Add stores the message in the overlay only.
+func (m *overlayMemory) Add(message Message) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.pending = append(m.pending, message)
+}
+
+// Get returns the base messages followed by the overlay's own.
+func (m *overlayMemory) Get() []Message {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	// Copy the base messages: appending to the returned slice could write into the base's own array.
+	base := m.base.Get()
+	messages := make([]Message, 0, len(base)+len(m.pending))
+	messages = append(messages, base...)
+	return append(messages, m.pending...)
+}
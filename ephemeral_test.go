@@ -0,0 +1,124 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// TestEphemeralChat verifica que un chat efímero lea el historial sin modificar la memoria.
+func TestEphemeralChat(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "c1", Name: "lookup", Args: json.RawMessage(`{}`)}),
+		textResponse("positivo"),
+	}}
+	mem := NewSimpleMemory()
+	mem.Add(Message{Role: RoleUser, Content: "mensaje previo", Name: "user"})
+
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("classifier"),
+		WithMemory(mem),
+		WithModel("gpt-4o"),
+		WithTool(&fakeTool{
+			def:      ToolDefinition{Name: "lookup"},
+			execFunc: func(args json.RawMessage) (interface{}, error) { return "dato", nil },
+		}),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	result, err := agent.(DetailedAgent).ChatDetailed(context.Background(),
+		WithUserName("user"), WithInput("clasifica esto"), WithEphemeral())
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if result.Content != "positivo" || len(result.Messages) != 4 {
+		t.Errorf("resultado inesperado: %s %+v", result.Content, result.Messages)
+	}
+
+	// El segundo request debe ver el historial previo y los mensajes de la herramienta.
+	if msgs := client.Requests()[1].Messages; len(msgs) != 4 || msgs[0].Content != "mensaje previo" {
+		t.Errorf("mensajes inesperados en el segundo request: %+v", msgs)
+	}
+	if msgs := mem.Get(); len(msgs) != 1 {
+		t.Errorf("la memoria no debía modificarse: %+v", msgs)
+	}
+}
+
+// TestEphemeralChatSpareCapacity verifica que un chat efímero no escriba en el arreglo de una memoria que devuelve su slice interno.
+func TestEphemeralChatSpareCapacity(t *testing.T) {
+	internal := make([]Message, 1, 8)
+	internal[0] = Message{Role: RoleUser, Content: "mensaje previo", Name: "user"}
+	mem, err := NewMemory(
+		WithAddHandler(func(message Message) { internal = append(internal, message) }),
+		WithGetHandler(func() []Message { return internal }),
+	)
+	if err != nil {
+		t.Fatalf("error creando memoria: %v", err)
+	}
+
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("positivo")}}
+	agent, err := NewAgent(WithClient(client), WithName("classifier"), WithMemory(mem), WithModel("gpt-4o"))
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("clasifica esto"), WithEphemeral()); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if len(internal) != 1 {
+		t.Errorf("la memoria no debía modificarse: %+v", internal)
+	}
+	if spare := internal[:2]; spare[1].Content != "" {
+		t.Errorf("el chat efímero escribió en la capacidad libre de la memoria: %+v", spare[1])
+	}
+}
+
+// TestDryRunChat verifica que el modo de prueba devuelva el request sin llamar al proveedor.
+func TestDryRunChat(t *testing.T) {
+	client := &recordingLLMClient{}
+	mem := NewSimpleMemory()
+
+	agent, _ := NewAgent(
+		WithClient(client),
+		WithName("agent"),
+		WithSystemPrompt("Eres útil."),
+		WithMemory(mem),
+		WithModel("gpt-4o"),
+		WithTool(&fakeTool{def: ToolDefinition{Name: "lookup"}}),
+		WithHooks(Hooks{
+			BeforeRequest: func(ctx context.Context, req *ChatCompletionRequest) error {
+				req.Temperature = 0.3
+				return nil
+			},
+		}),
+	)
+
+	result, err := agent.(DetailedAgent).ChatDetailed(context.Background(),
+		WithUserName("user"), WithInput("hola"), WithDryRun())
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if len(client.Requests()) != 0 {
+		t.Error("no se debía llamar al proveedor")
+	}
+	req := result.Request
+	if req == nil || req.Model != "gpt-4o" || req.Temperature != 0.3 || len(req.Tools) != 1 || len(req.Messages) != 2 {
+		t.Fatalf("request inesperado: %+v", req)
+	}
+	if req.Messages[0].Content != "Eres útil." || req.Messages[1].Content != "hola" {
+		t.Errorf("mensajes inesperados: %+v", req.Messages)
+	}
+	if result.Content != "" || result.Rounds != 0 {
+		t.Errorf("un dry run no debía producir respuesta ni rondas: %+v", result)
+	}
+	if len(mem.Get()) != 0 {
+		t.Error("un dry run no debía escribir en memoria")
+	}
+
+	answer, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("hola"), WithDryRun())
+	if err != nil || answer != "" {
+		t.Errorf("Chat con dry run debía devolver una respuesta vacía: %q %v", answer, err)
+	}
+}
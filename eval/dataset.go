@@ -0,0 +1,132 @@
+package eval
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Case is a single evaluation case: an input for the target and what a good answer looks like.
+// Graders decide which of the expectations apply.
+type Case struct {
+	ID       string `json:"id" yaml:"id"`
+	Input    string `json:"input" yaml:"input"`
+	UserName string `json:"user_name,omitempty" yaml:"user_name"`
+	// Expected is the reference answer used by the Exact, Contains and Numeric graders.
+	Expected string `json:"expected,omitempty" yaml:"expected"`
+	// ExpectedToolCalls lists the tools the target should call, in order, for the ToolCalls grader.
+	ExpectedToolCalls []string       `json:"expected_tool_calls,omitempty" yaml:"expected_tool_calls"`
+	Tags              []string       `json:"tags,omitempty" yaml:"tags"`
+	Metadata          map[string]any `json:"metadata,omitempty" yaml:"metadata"`
+}
+
+// Dataset is a named list of evaluation cases.
+type Dataset struct {
+	Name  string `json:"name" yaml:"name"`
+	Cases []Case `json:"cases" yaml:"cases"`
+}
+
+// LoadDataset reads a dataset from a JSONL file (one case per line) or a YAML file,
+// chosen by the file extension. The dataset is named after the file.
+func LoadDataset(path string) (*Dataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+
+	var dataset *Dataset
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".jsonl", ".ndjson":
+		dataset, err = ParseJSONL(bytes.NewReader(data))
+	case ".yaml", ".yml":
+		dataset, err = ParseYAML(data)
+	default:
+		return nil, fmt.Errorf("unsupported dataset format %q: use .jsonl or .yaml", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if dataset.Name == "" {
+		dataset.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return dataset, nil
+}
+
+// ParseJSONL reads a dataset with one JSON case per line. Blank lines are ignored.
+func ParseJSONL(r io.Reader) (*Dataset, error) {
+	dataset := &Dataset{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		decoder := json.NewDecoder(strings.NewReader(text))
+		decoder.DisallowUnknownFields()
+		var c Case
+		if err := decoder.Decode(&c); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		dataset.Cases = append(dataset.Cases, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	return dataset, dataset.validate()
+}
+
+// ParseYAML reads a dataset from YAML: either a list of cases or a mapping with
+// a name and a cases list.
+func ParseYAML(data []byte) (*Dataset, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	dataset := &Dataset{}
+	if len(root.Content) == 0 {
+		return dataset, dataset.validate()
+	}
+
+	doc := root.Content[0]
+	var err error
+	if doc.Kind == yaml.SequenceNode {
+		err = doc.Decode(&dataset.Cases)
+	} else {
+		err = doc.Decode(dataset)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dataset, dataset.validate()
+}
+
+// validate checks the cases and names those without an ID after their position.
+func (d *Dataset) validate() error {
+	if len(d.Cases) == 0 {
+		return errors.New("dataset has no cases")
+	}
+	seen := make(map[string]bool, len(d.Cases))
+	for i := range d.Cases {
+		c := &d.Cases[i]
+		if c.ID == "" {
+			c.ID = fmt.Sprintf("case-%d", i+1)
+		}
+		if seen[c.ID] {
+			return fmt.Errorf("duplicate case ID %q", c.ID)
+		}
+		seen[c.ID] = true
+		if c.Input == "" {
+			return fmt.Errorf("case %s has no input", c.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package eval
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadDatasetJSONL verifica la lectura de casos en JSONL y los IDs por defecto.
+func TestLoadDatasetJSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "support.jsonl")
+	content := `{"id":"saludo","input":"hola","expected":"hola"}
+
+{"input":"¿clima en Lima?","expected_tool_calls":["get_weather"],"tags":["tools"]}
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dataset, err := LoadDataset(path)
+	if err != nil {
+		t.Fatalf("error cargando dataset: %v", err)
+	}
+	if dataset.Name != "support" || len(dataset.Cases) != 2 {
+		t.Fatalf("dataset inesperado: %+v", dataset)
+	}
+	if dataset.Cases[1].ID != "case-2" || dataset.Cases[1].ExpectedToolCalls[0] != "get_weather" {
+		t.Errorf("segundo caso inesperado: %+v", dataset.Cases[1])
+	}
+
+	_, err = ParseJSONL(strings.NewReader(`{"id":"a","input":"x"}` + "\n" + `{"id":"b","inptu":"y"}`))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("se esperaba un error en la línea 2, se obtuvo %v", err)
+	}
+}
+
+// TestParseYAML verifica los dos formatos YAML y la validación de los casos.
+func TestParseYAML(t *testing.T) {
+	dataset, err := ParseYAML([]byte(`
+name: matemáticas
+cases:
+  - id: suma
+    input: "¿cuánto es 2+2?"
+    expected: "4"
+`))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if dataset.Name != "matemáticas" || dataset.Cases[0].Expected != "4" {
+		t.Errorf("dataset inesperado: %+v", dataset)
+	}
+
+	dataset, err = ParseYAML([]byte("- input: uno\n- input: dos\n"))
+	if err != nil || len(dataset.Cases) != 2 || dataset.Cases[0].ID != "case-1" {
+		t.Errorf("lista de casos inesperada: %+v %v", dataset, err)
+	}
+
+	if _, err := ParseYAML([]byte("- id: a\n  input: uno\n- id: a\n  input: dos\n")); err == nil || !strings.Contains(err.Error(), "duplicate") {
+		t.Errorf("se esperaba error por ID duplicado, se obtuvo %v", err)
+	}
+	if _, err := ParseYAML([]byte("- id: a\n")); err == nil {
+		t.Error("se esperaba error por caso sin input")
+	}
+}
@@ -0,0 +1,110 @@
+package eval
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Diff compares two runs over the same dataset.
+type Diff struct {
+	Previous, Current Summary
+
+	PassRateDelta    float64       // Current pass rate minus the previous one.
+	CostDelta        float64       // Current cost minus the previous one.
+	TokensDelta      int           // Current total tokens minus the previous ones.
+	MeanLatencyDelta time.Duration // Current mean latency minus the previous one.
+
+	Regressions []CaseChange // Cases that passed before and do not pass now.
+	Fixes       []CaseChange // Cases that did not pass before and pass now.
+	Added       []string     // Cases only in the current run.
+	Removed     []string     // Cases only in the previous run.
+}
+
+// CaseChange describes a case whose outcome changed between two runs.
+type CaseChange struct {
+	ID             string
+	PreviousOutput string
+	CurrentOutput  string
+	Reason         string // Why the case does not pass in the run where it fails.
+}
+
+// Compare returns the differences between a previous run and the current one, matching cases by ID.
+func Compare(previous, current *Report) *Diff {
+	d := &Diff{
+		Previous:         previous.Summary,
+		Current:          current.Summary,
+		PassRateDelta:    current.Summary.PassRate - previous.Summary.PassRate,
+		CostDelta:        current.Summary.Cost - previous.Summary.Cost,
+		TokensDelta:      current.Summary.Usage.TotalTokens - previous.Summary.Usage.TotalTokens,
+		MeanLatencyDelta: current.Summary.Latency.Mean - previous.Summary.Latency.Mean,
+	}
+
+	before := make(map[string]CaseResult, len(previous.Cases))
+	for _, c := range previous.Cases {
+		before[c.ID] = c
+	}
+	seen := make(map[string]bool, len(current.Cases))
+	for _, now := range current.Cases {
+		seen[now.ID] = true
+		then, ok := before[now.ID]
+		if !ok {
+			d.Added = append(d.Added, now.ID)
+			continue
+		}
+		change := CaseChange{ID: now.ID, PreviousOutput: then.Output, CurrentOutput: now.Output}
+		switch {
+		case then.Passed && !now.Passed:
+			change.Reason = now.failureReason()
+			d.Regressions = append(d.Regressions, change)
+		case !then.Passed && now.Passed:
+			change.Reason = then.failureReason()
+			d.Fixes = append(d.Fixes, change)
+		}
+	}
+	for _, c := range previous.Cases {
+		if !seen[c.ID] {
+			d.Removed = append(d.Removed, c.ID)
+		}
+	}
+	return d
+}
+
+// WriteText writes a human-readable summary of the differences.
+func (d *Diff) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Pass rate: %.1f%% -> %.1f%% (%+.1f points)\n", d.Previous.PassRate*100, d.Current.PassRate*100, d.PassRateDelta*100)
+	fmt.Fprintf(&b, "Cost:      %.4f -> %.4f (%+.4f)\n", d.Previous.Cost, d.Current.Cost, d.CostDelta)
+	fmt.Fprintf(&b, "Tokens:    %d -> %d (%+d)\n", d.Previous.Usage.TotalTokens, d.Current.Usage.TotalTokens, d.TokensDelta)
+	fmt.Fprintf(&b, "Latency:   mean %s -> %s (%s)\n", round(d.Previous.Latency.Mean), round(d.Current.Latency.Mean), signed(d.MeanLatencyDelta))
+
+	writeChanges := func(title string, changes []CaseChange) {
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s (%d):\n", title, len(changes))
+		for _, c := range changes {
+			fmt.Fprintf(&b, "  %s: %s\n", c.ID, c.Reason)
+		}
+	}
+	writeChanges("Regressions", d.Regressions)
+	writeChanges("Fixes", d.Fixes)
+	if len(d.Added) > 0 {
+		fmt.Fprintf(&b, "\nAdded: %s\n", strings.Join(d.Added, ", "))
+	}
+	if len(d.Removed) > 0 {
+		fmt.Fprintf(&b, "\nRemoved: %s\n", strings.Join(d.Removed, ", "))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// signed formats a duration difference with its sign.
+func signed(d time.Duration) string {
+	if d >= 0 {
+		return "+" + round(d).String()
+	}
+	return round(d).String()
+}
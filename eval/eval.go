@@ -0,0 +1,200 @@
+// Package eval runs agents and syndicates over datasets of cases and grades their answers,
+// so prompt, model and tool changes can be measured and compared from one run to the next.
+//
+// Example:
+//
+//	dataset, err := eval.LoadDataset("testdata/support.jsonl")
+//	report, err := eval.Run(ctx, dataset, eval.AgentTarget(agent),
+//		eval.WithGraders(eval.Contains(), eval.ToolCalls()),
+//		eval.WithConcurrency(8),
+//		eval.WithPricing(eval.Pricing{PromptPerMillion: 2.5, CompletionPerMillion: 10}),
+//	)
+//	report.WriteText(os.Stdout)
+//
+//	previous, err := eval.LoadReport("baseline.json")
+//	eval.Compare(previous, report).WriteText(os.Stdout)
+package eval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	syndicate "github.com/Dieg0Code/syndicate-go"
+)
+
+// defaultConcurrency is the number of cases run at the same time when none is configured.
+const defaultConcurrency = 4
+
+// Pricing converts token usage into cost, in any currency, per million tokens.
+type Pricing struct {
+	PromptPerMillion     float64
+	CompletionPerMillion float64
+}
+
+// Cost returns the cost of the given usage.
+func (p Pricing) Cost(usage syndicate.Usage) float64 {
+	return (float64(usage.PromptTokens)*p.PromptPerMillion + float64(usage.CompletionTokens)*p.CompletionPerMillion) / 1e6
+}
+
+// Option configures an evaluation run.
+type Option func(*runner) error
+
+// runner holds the configuration of an evaluation run.
+type runner struct {
+	name        string
+	graders     []Grader
+	concurrency int
+	pricing     Pricing
+	caseTimeout time.Duration
+}
+
+// WithGraders adds graders; a case passes when it runs without error and every grader passes it.
+func WithGraders(graders ...Grader) Option {
+	return func(r *runner) error {
+		for _, g := range graders {
+			if g.Name == "" {
+				return errors.New("grader name cannot be empty")
+			}
+			if g.Check == nil {
+				return fmt.Errorf("grader %s has no check function", g.Name)
+			}
+		}
+		r.graders = append(r.graders, graders...)
+		return nil
+	}
+}
+
+// WithConcurrency sets how many cases run at the same time. It defaults to 4.
+func WithConcurrency(n int) Option {
+	return func(r *runner) error {
+		if n < 1 {
+			return errors.New("concurrency must be at least 1")
+		}
+		r.concurrency = n
+		return nil
+	}
+}
+
+// WithPricing sets the token prices used to report the cost of each case.
+func WithPricing(pricing Pricing) Option {
+	return func(r *runner) error {
+		if pricing.PromptPerMillion < 0 || pricing.CompletionPerMillion < 0 {
+			return errors.New("prices cannot be negative")
+		}
+		r.pricing = pricing
+		return nil
+	}
+}
+
+// WithCaseTimeout limits how long each case may run; a case that times out is reported as errored.
+func WithCaseTimeout(timeout time.Duration) Option {
+	return func(r *runner) error {
+		if timeout <= 0 {
+			return errors.New("case timeout must be positive")
+		}
+		r.caseTimeout = timeout
+		return nil
+	}
+}
+
+// WithRunName names the run in its report, for example after the prompt version or commit being evaluated.
+func WithRunName(name string) Option {
+	return func(r *runner) error {
+		r.name = name
+		return nil
+	}
+}
+
+// Run runs every case of the dataset against the target and grades the results.
+// Case failures and errors are recorded in the report; Run only fails on invalid arguments.
+// When ctx is cancelled, the cases that did not run are reported as errored.
+func Run(ctx context.Context, dataset *Dataset, target Target, options ...Option) (*Report, error) {
+	if dataset == nil || len(dataset.Cases) == 0 {
+		return nil, errors.New("dataset has no cases")
+	}
+	if target == nil {
+		return nil, errors.New("target cannot be nil")
+	}
+	r := &runner{concurrency: defaultConcurrency}
+	for _, option := range options {
+		if err := option(r); err != nil {
+			return nil, fmt.Errorf("failed to apply option: %w", err)
+		}
+	}
+
+	report := &Report{
+		Name:      r.name,
+		Dataset:   dataset.Name,
+		StartedAt: time.Now(),
+		Cases:     make([]CaseResult, len(dataset.Cases)),
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, r.concurrency)
+	for i, c := range dataset.Cases {
+		wg.Add(1)
+		go func(i int, c Case) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+			}
+			// select picks at random when both cases are ready, so cancellation is checked again here.
+			if err := ctx.Err(); err != nil {
+				report.Cases[i] = CaseResult{ID: c.ID, Input: c.Input, Expected: c.Expected, Error: err.Error()}
+				return
+			}
+			report.Cases[i] = r.runCase(ctx, target, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report.Duration = time.Since(report.StartedAt)
+	report.Summary = summarize(report.Cases)
+	return report, nil
+}
+
+// runCase runs and grades a single case.
+func (r *runner) runCase(ctx context.Context, target Target, c Case) CaseResult {
+	cr := CaseResult{ID: c.ID, Input: c.Input, Expected: c.Expected}
+	if r.caseTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.caseTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	result, err := target.Run(ctx, c)
+	cr.Latency = time.Since(start)
+	if err != nil {
+		cr.Error = err.Error()
+		return cr
+	}
+	if result == nil {
+		cr.Error = "target returned no result"
+		return cr
+	}
+
+	cr.Output = result.Content
+	cr.ToolCalls = toolNames(result)
+	cr.Usage = result.Usage
+	cr.Cost = r.pricing.Cost(result.Usage)
+
+	cr.Passed = true
+	for _, g := range r.graders {
+		grade, err := g.Check(ctx, c, result)
+		if err != nil {
+			cr.Error = fmt.Sprintf("grader %s failed: %v", g.Name, err)
+			cr.Passed = false
+			return cr
+		}
+		grade.Grader = g.Name
+		cr.Grades = append(cr.Grades, grade)
+		cr.Passed = cr.Passed && grade.Passed
+	}
+	return cr
+}
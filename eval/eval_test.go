@@ -0,0 +1,209 @@
+package eval
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	syndicate "github.com/Dieg0Code/syndicate-go"
+)
+
+// echoClient responde con la última entrada del usuario en mayúsculas y cuenta los requests en curso.
+type echoClient struct {
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (c *echoClient) CreateChatCompletion(ctx context.Context, req syndicate.ChatCompletionRequest) (syndicate.ChatCompletionResponse, error) {
+	now := c.running.Add(1)
+	defer c.running.Add(-1)
+	for {
+		peak := c.peak.Load()
+		if now <= peak || c.peak.CompareAndSwap(peak, now) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	input := req.Messages[len(req.Messages)-1].Content
+	if input == "falla" {
+		return syndicate.ChatCompletionResponse{}, errors.New("proveedor caído")
+	}
+	return syndicate.ChatCompletionResponse{
+		Choices: []syndicate.Choice{{
+			Message:      syndicate.Message{Role: syndicate.RoleAssistant, Content: strings.ToUpper(input)},
+			FinishReason: syndicate.FinishReasonStop,
+		}},
+		Usage: syndicate.Usage{PromptTokens: 1000, CompletionTokens: 500, TotalTokens: 1500},
+	}, nil
+}
+
+// newEvalAgent crea un agente que repite la entrada en mayúsculas.
+func newEvalAgent(t *testing.T, client syndicate.LLMClient) syndicate.Agent {
+	t.Helper()
+	agent, err := syndicate.NewAgent(
+		syndicate.WithClient(client),
+		syndicate.WithName("echo"),
+		syndicate.WithMemory(syndicate.NewSimpleMemory()),
+		syndicate.WithModel("gpt-4o"),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+	return agent
+}
+
+// TestRunReport verifica la ejecución concurrente, el informe y su persistencia.
+func TestRunReport(t *testing.T) {
+	client := &echoClient{}
+	agent := newEvalAgent(t, client)
+	dataset := &Dataset{Name: "eco", Cases: []Case{
+		{ID: "a", Input: "hola", Expected: "HOLA"},
+		{ID: "b", Input: "adiós", Expected: "ADIÓS"},
+		{ID: "c", Input: "mal", Expected: "BIEN"},
+		{ID: "d", Input: "falla", Expected: "FALLA"},
+	}}
+
+	report, err := Run(context.Background(), dataset, AgentTarget(agent),
+		WithGraders(Exact()),
+		WithConcurrency(2),
+		WithPricing(Pricing{PromptPerMillion: 2, CompletionPerMillion: 8}),
+		WithRunName("v1"),
+	)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	s := report.Summary
+	if s.Cases != 4 || s.Passed != 2 || s.Failed != 1 || s.Errored != 1 || s.PassRate != 0.5 {
+		t.Errorf("resumen inesperado: %+v", s)
+	}
+	if s.Usage.TotalTokens != 4500 || s.Cost < 0.017999 || s.Cost > 0.018001 {
+		t.Errorf("uso o costo inesperado: %+v %f", s.Usage, s.Cost)
+	}
+	if s.Graders["exact"].Graded != 3 || s.Graders["exact"].Passed != 2 {
+		t.Errorf("resumen del grader inesperado: %+v", s.Graders)
+	}
+	if s.Latency.Max < 5*time.Millisecond || s.Latency.P50 > s.Latency.Max {
+		t.Errorf("latencias inesperadas: %+v", s.Latency)
+	}
+	if peak := client.peak.Load(); peak > 2 {
+		t.Errorf("se superó la concurrencia configurada: %d", peak)
+	}
+	if report.Cases[2].ID != "c" || report.Cases[2].Passed || !strings.Contains(report.Cases[3].Error, "proveedor caído") {
+		t.Errorf("casos inesperados: %+v", report.Cases)
+	}
+
+	var text bytes.Buffer
+	if err := report.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"eco (v1)", "Pass rate: 50.0%", "c: exact", "d: error"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("el informe no contiene %q:\n%s", want, text.String())
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := report.Save(path); err != nil {
+		t.Fatalf("error guardando informe: %v", err)
+	}
+	loaded, err := LoadReport(path)
+	if err != nil || loaded.Summary.Passed != 2 || loaded.Cases[0].Latency != report.Cases[0].Latency {
+		t.Errorf("informe cargado inesperado: %+v %v", loaded, err)
+	}
+}
+
+// TestAgentTargetDeletesSessions verifica que cada caso borre su sesión al terminar.
+func TestAgentTargetDeletesSessions(t *testing.T) {
+	var created atomic.Int32
+	agent, err := syndicate.NewAgent(
+		syndicate.WithClient(&echoClient{}),
+		syndicate.WithName("echo"),
+		syndicate.WithModel("gpt-4o"),
+		syndicate.WithMemoryFactory(func(sessionID string) (syndicate.Memory, error) {
+			created.Add(1)
+			return syndicate.NewSimpleMemory(), nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+	dataset := &Dataset{Name: "eco", Cases: []Case{{ID: "a", Input: "hola"}, {ID: "b", Input: "falla"}}}
+
+	if _, err := Run(context.Background(), dataset, AgentTarget(agent)); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	sessions := agent.(syndicate.SessionAgent)
+	for _, id := range []string{"eval/a", "eval/b"} {
+		if sessions.DeleteSession(id) {
+			t.Errorf("la sesión %s debía haberse borrado", id)
+		}
+	}
+	if created.Load() != 2 {
+		t.Errorf("se esperaban 2 memorias creadas, hubo %d", created.Load())
+	}
+}
+
+// TestCompareReports verifica las regresiones, correcciones y casos nuevos entre dos ejecuciones.
+func TestCompareReports(t *testing.T) {
+	previous := &Report{Cases: []CaseResult{
+		{ID: "a", Passed: true, Output: "HOLA"},
+		{ID: "b", Passed: false, Output: "x", Grades: []Grade{{Grader: "exact", Reason: "expected \"B\""}}},
+		{ID: "old", Passed: true},
+	}}
+	current := &Report{Cases: []CaseResult{
+		{ID: "a", Passed: false, Output: "hola", Grades: []Grade{{Grader: "exact", Reason: "expected \"HOLA\""}}},
+		{ID: "b", Passed: true, Output: "B"},
+		{ID: "new", Passed: true},
+	}}
+	previous.Summary = summarize(previous.Cases)
+	current.Summary = summarize(current.Cases)
+
+	diff := Compare(previous, current)
+	if len(diff.Regressions) != 1 || diff.Regressions[0].ID != "a" || diff.Regressions[0].PreviousOutput != "HOLA" {
+		t.Errorf("regresiones inesperadas: %+v", diff.Regressions)
+	}
+	if len(diff.Fixes) != 1 || diff.Fixes[0].ID != "b" {
+		t.Errorf("correcciones inesperadas: %+v", diff.Fixes)
+	}
+	if strings.Join(diff.Added, ",") != "new" || strings.Join(diff.Removed, ",") != "old" {
+		t.Errorf("casos añadidos o eliminados inesperados: %v %v", diff.Added, diff.Removed)
+	}
+
+	var text bytes.Buffer
+	if err := diff.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "Regressions (1):\n  a: exact (expected \"HOLA\")") {
+		t.Errorf("texto del diff inesperado:\n%s", text.String())
+	}
+}
+
+// TestRunOptionsAndCancellation verifica la validación de opciones y los casos no ejecutados al cancelar.
+func TestRunOptionsAndCancellation(t *testing.T) {
+	agent := newEvalAgent(t, &echoClient{})
+	dataset := &Dataset{Cases: []Case{{ID: "a", Input: "hola"}}}
+
+	if _, err := Run(context.Background(), dataset, AgentTarget(agent), WithConcurrency(0)); err == nil {
+		t.Error("se esperaba error por concurrencia inválida")
+	}
+	if _, err := Run(context.Background(), &Dataset{}, AgentTarget(agent)); err == nil {
+		t.Error("se esperaba error por dataset vacío")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := Run(ctx, dataset, AgentTarget(agent))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if report.Summary.Errored != 1 {
+		t.Errorf("el caso debía quedar con error al cancelar: %+v", report.Cases)
+	}
+}
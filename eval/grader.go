@@ -0,0 +1,283 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	syndicate "github.com/Dieg0Code/syndicate-go"
+)
+
+// Grade is a grader's verdict on one case.
+type Grade struct {
+	Grader string  `json:"grader"`
+	Passed bool    `json:"passed"`
+	Score  float64 `json:"score"` // From 0 to 1.
+	Reason string  `json:"reason,omitempty"`
+}
+
+// Grader judges the result of a case.
+type Grader struct {
+	Name string
+	// Check grades the result. An error marks the case as errored rather than failed.
+	Check func(ctx context.Context, c Case, result *syndicate.ChatResult) (Grade, error)
+}
+
+// pass returns a passing grade with a full score.
+func pass() Grade {
+	return Grade{Passed: true, Score: 1}
+}
+
+// fail returns a failing grade with a null score.
+func fail(reason string) Grade {
+	return Grade{Reason: reason}
+}
+
+// failf returns a failing grade with a formatted reason.
+func failf(format string, args ...any) Grade {
+	return fail(fmt.Sprintf(format, args...))
+}
+
+// Exact passes when the answer equals the case's expected answer, ignoring surrounding whitespace.
+func Exact() Grader {
+	return Grader{
+		Name: "exact",
+		Check: func(ctx context.Context, c Case, result *syndicate.ChatResult) (Grade, error) {
+			if strings.TrimSpace(result.Content) == strings.TrimSpace(c.Expected) {
+				return pass(), nil
+			}
+			return failf("expected %q", c.Expected), nil
+		},
+	}
+}
+
+// Contains passes when the answer contains every given substring, ignoring case.
+// Without substrings, it looks for the case's expected answer.
+func Contains(substrings ...string) Grader {
+	return Grader{
+		Name: "contains",
+		Check: func(ctx context.Context, c Case, result *syndicate.ChatResult) (Grade, error) {
+			wanted := substrings
+			if len(wanted) == 0 {
+				wanted = []string{c.Expected}
+			}
+			content := strings.ToLower(result.Content)
+			var missing []string
+			for _, s := range wanted {
+				if !strings.Contains(content, strings.ToLower(s)) {
+					missing = append(missing, strconv.Quote(s))
+				}
+			}
+			if len(missing) == 0 {
+				return pass(), nil
+			}
+			grade := failf("missing %s", strings.Join(missing, ", "))
+			grade.Score = 1 - float64(len(missing))/float64(len(wanted))
+			return grade, nil
+		},
+	}
+}
+
+// Regex passes when the answer matches pattern.
+func Regex(pattern *regexp.Regexp) Grader {
+	return Grader{
+		Name: "regex",
+		Check: func(ctx context.Context, c Case, result *syndicate.ChatResult) (Grade, error) {
+			if pattern.MatchString(result.Content) {
+				return pass(), nil
+			}
+			return failf("does not match %s", pattern), nil
+		},
+	}
+}
+
+// JSONSchema passes when the answer is JSON conforming to schema, which may be a json.RawMessage
+// or a value to generate the schema from (see syndicate.GenerateRawSchema). Code fences around
+// the JSON are ignored.
+func JSONSchema(schema any) (Grader, error) {
+	raw, ok := schema.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = syndicate.GenerateRawSchema(schema); err != nil {
+			return Grader{}, fmt.Errorf("failed to generate schema: %w", err)
+		}
+	}
+	if !json.Valid(raw) {
+		return Grader{}, errors.New("schema is not valid JSON")
+	}
+	return Grader{
+		Name: "json_schema",
+		Check: func(ctx context.Context, c Case, result *syndicate.ChatResult) (Grade, error) {
+			document := json.RawMessage(trimCodeFence(result.Content))
+			if err := syndicate.ValidateJSON(raw, document); err != nil {
+				return fail(err.Error()), nil
+			}
+			return pass(), nil
+		},
+	}, nil
+}
+
+// ToolCalls passes when the target called the case's expected tools in the given order; other
+// calls may happen in between. The score is the fraction of expected calls found.
+func ToolCalls() Grader {
+	return Grader{
+		Name: "tool_calls",
+		Check: func(ctx context.Context, c Case, result *syndicate.ChatResult) (Grade, error) {
+			if len(c.ExpectedToolCalls) == 0 {
+				return pass(), nil
+			}
+			found := 0
+			for _, call := range result.ToolCalls {
+				if found < len(c.ExpectedToolCalls) && call.Call.Name == c.ExpectedToolCalls[found] {
+					found++
+				}
+			}
+			if found == len(c.ExpectedToolCalls) {
+				return pass(), nil
+			}
+			grade := failf("expected tool calls %v, got %v", c.ExpectedToolCalls, toolNames(result))
+			grade.Score = float64(found) / float64(len(c.ExpectedToolCalls))
+			return grade, nil
+		},
+	}
+}
+
+// numberPattern matches a decimal number, with optional sign, thousands separators and exponent.
+var numberPattern = regexp.MustCompile(`[-+]?(?:\d{1,3}(?:,\d{3})+|\d+)(?:\.\d+)?(?:[eE][-+]?\d+)?`)
+
+// Numeric passes when the first number in the answer is within tolerance of the first number in the
+// case's expected answer.
+func Numeric(tolerance float64) Grader {
+	return Grader{
+		Name: "numeric",
+		Check: func(ctx context.Context, c Case, result *syndicate.ChatResult) (Grade, error) {
+			expected, ok := firstNumber(c.Expected)
+			if !ok {
+				return Grade{}, fmt.Errorf("case %s has no expected number", c.ID)
+			}
+			got, ok := firstNumber(result.Content)
+			if !ok {
+				return fail("answer contains no number"), nil
+			}
+			if diff := math.Abs(got - expected); diff > tolerance {
+				return failf("got %g, expected %g ± %g", got, expected, tolerance), nil
+			}
+			return pass(), nil
+		},
+	}
+}
+
+// firstNumber returns the first number in s.
+func firstNumber(s string) (float64, bool) {
+	match := numberPattern.FindString(s)
+	if match == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(match, ",", ""), 64)
+	return n, err == nil
+}
+
+// defaultJudgePassScore is the judge score, from 0 to 10, needed to pass when none is given.
+const defaultJudgePassScore = 7
+
+// judgeInstructions asks the judge to grade an answer; %s is replaced by the rubric.
+const judgeInstructions = `You grade answers given by an AI assistant. Grade the answer against this rubric:
+
+%s
+
+Give a score from 0 (useless) to 10 (perfect) and explain the score in one or two sentences.`
+
+// judgeOutput is the response format requested from the judge.
+type judgeOutput struct {
+	Score  float64 `json:"score" description:"Score from 0 to 10"`
+	Reason string  `json:"reason" description:"Why the answer got this score"`
+}
+
+// LLMJudge asks a model to score the answer from 0 to 10 against a rubric written in natural language.
+// The case passes when the score reaches passScore (7 when zero); the grade's score is the judge's
+// score divided by 10. The judge sees the case input, its expected answer when set, and the answer.
+func LLMJudge(client syndicate.LLMClient, model, rubric string, passScore float64) (Grader, error) {
+	if client == nil {
+		return Grader{}, errors.New("judge client cannot be nil")
+	}
+	if model == "" {
+		return Grader{}, errors.New("judge model cannot be empty")
+	}
+	if rubric == "" {
+		return Grader{}, errors.New("rubric cannot be empty")
+	}
+	if passScore == 0 {
+		passScore = defaultJudgePassScore
+	}
+	if passScore < 0 || passScore > 10 {
+		return Grader{}, errors.New("pass score must be between 0 and 10")
+	}
+	schema, err := syndicate.GenerateRawSchema(judgeOutput{})
+	if err != nil {
+		return Grader{}, fmt.Errorf("error generating schema: %w", err)
+	}
+	format := &syndicate.ResponseFormat{
+		Type:       "json_schema",
+		JSONSchema: &syndicate.JSONSchema{Name: "grade", Schema: schema, Strict: true},
+	}
+
+	return Grader{
+		Name: "llm_judge",
+		Check: func(ctx context.Context, c Case, result *syndicate.ChatResult) (Grade, error) {
+			var prompt strings.Builder
+			fmt.Fprintf(&prompt, "Request:\n%s\n\n", c.Input)
+			if c.Expected != "" {
+				fmt.Fprintf(&prompt, "Reference answer:\n%s\n\n", c.Expected)
+			}
+			fmt.Fprintf(&prompt, "Answer to grade:\n%s", result.Content)
+
+			resp, err := client.CreateChatCompletion(ctx, syndicate.ChatCompletionRequest{
+				Model: model,
+				Messages: []syndicate.Message{
+					{Role: syndicate.RoleSystem, Content: fmt.Sprintf(judgeInstructions, rubric)},
+					{Role: syndicate.RoleUser, Content: prompt.String()},
+				},
+				ResponseFormat: format,
+			})
+			if err != nil {
+				return Grade{}, fmt.Errorf("error in judgement: %w", err)
+			}
+			if len(resp.Choices) == 0 {
+				return Grade{}, errors.New("no judgement choices available")
+			}
+			var judgement judgeOutput
+			if err := json.Unmarshal([]byte(trimCodeFence(resp.Choices[0].Message.Content)), &judgement); err != nil {
+				return Grade{}, fmt.Errorf("invalid judgement: %w", err)
+			}
+			score := math.Max(0, math.Min(10, judgement.Score))
+			return Grade{Passed: score >= passScore, Score: score / 10, Reason: judgement.Reason}, nil
+		},
+	}, nil
+}
+
+// trimCodeFence removes a Markdown code fence around s, if any.
+func trimCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```")
+	if newline := strings.IndexByte(s, '\n'); newline >= 0 {
+		s = s[newline+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "```"))
+}
+
+// toolNames returns the names of the tools called in a result, in order.
+func toolNames(result *syndicate.ChatResult) []string {
+	names := make([]string, len(result.ToolCalls))
+	for i, call := range result.ToolCalls {
+		names[i] = call.Call.Name
+	}
+	return names
+}
@@ -0,0 +1,132 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	syndicate "github.com/Dieg0Code/syndicate-go"
+)
+
+// judgeClient responde siempre con el mismo texto y guarda los requests.
+type judgeClient struct {
+	mu       sync.Mutex
+	response string
+	requests []syndicate.ChatCompletionRequest
+}
+
+func (c *judgeClient) CreateChatCompletion(ctx context.Context, req syndicate.ChatCompletionRequest) (syndicate.ChatCompletionResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	return syndicate.ChatCompletionResponse{Choices: []syndicate.Choice{{
+		Message:      syndicate.Message{Role: syndicate.RoleAssistant, Content: c.response},
+		FinishReason: syndicate.FinishReasonStop,
+	}}}, nil
+}
+
+// grade aplica un grader a una respuesta.
+func grade(t *testing.T, g Grader, c Case, result *syndicate.ChatResult) Grade {
+	t.Helper()
+	got, err := g.Check(context.Background(), c, result)
+	if err != nil {
+		t.Fatalf("error en %s: %v", g.Name, err)
+	}
+	return got
+}
+
+// TestTextGraders verifica los graders de texto.
+func TestTextGraders(t *testing.T) {
+	answer := &syndicate.ChatResult{Content: "  La capital es París.  "}
+
+	if !grade(t, Exact(), Case{Expected: "La capital es París."}, answer).Passed {
+		t.Error("exact debía ignorar los espacios")
+	}
+	if grade(t, Exact(), Case{Expected: "París"}, answer).Passed {
+		t.Error("exact no debía aceptar respuestas parciales")
+	}
+	if !grade(t, Contains(), Case{Expected: "parís"}, answer).Passed {
+		t.Error("contains debía usar la respuesta esperada sin distinguir mayúsculas")
+	}
+	if g := grade(t, Contains("capital", "Francia"), Case{}, answer); g.Passed || g.Score != 0.5 || !strings.Contains(g.Reason, "Francia") {
+		t.Errorf("contains parcial inesperado: %+v", g)
+	}
+	if !grade(t, Regex(regexp.MustCompile(`(?i)parís\.$`)), Case{}, &syndicate.ChatResult{Content: "Es París."}).Passed {
+		t.Error("regex debía coincidir")
+	}
+}
+
+// TestStructuredGraders verifica los graders de esquema, herramientas y números.
+func TestStructuredGraders(t *testing.T) {
+	type person struct {
+		Name string `json:"name" description:"Name"`
+		Age  int    `json:"age" description:"Age"`
+	}
+	schema, err := JSONSchema(person{})
+	if err != nil {
+		t.Fatalf("error creando grader: %v", err)
+	}
+	if !grade(t, schema, Case{}, &syndicate.ChatResult{Content: "```json\n{\"name\":\"Ana\",\"age\":3}\n```"}).Passed {
+		t.Error("el JSON válido debía pasar")
+	}
+	if g := grade(t, schema, Case{}, &syndicate.ChatResult{Content: `{"name":"Ana"}`}); g.Passed || !strings.Contains(g.Reason, "age") {
+		t.Errorf("el JSON incompleto no debía pasar: %+v", g)
+	}
+
+	calls := &syndicate.ChatResult{ToolCalls: []syndicate.ToolCallResult{
+		{Call: syndicate.ToolCall{Name: "search"}},
+		{Call: syndicate.ToolCall{Name: "log"}},
+		{Call: syndicate.ToolCall{Name: "book"}},
+	}}
+	if !grade(t, ToolCalls(), Case{ExpectedToolCalls: []string{"search", "book"}}, calls).Passed {
+		t.Error("las herramientas esperadas aparecen en orden")
+	}
+	if g := grade(t, ToolCalls(), Case{ExpectedToolCalls: []string{"book", "search"}}, calls); g.Passed || g.Score != 0.5 {
+		t.Errorf("el orden incorrecto no debía pasar: %+v", g)
+	}
+
+	numeric := Numeric(0.01)
+	if !grade(t, numeric, Case{Expected: "3.14"}, &syndicate.ChatResult{Content: "Pi vale aproximadamente 3.141"}).Passed {
+		t.Error("el número dentro de la tolerancia debía pasar")
+	}
+	if !grade(t, Numeric(0), Case{Expected: "1200"}, &syndicate.ChatResult{Content: "Total: 1,200 USD"}).Passed {
+		t.Error("los separadores de miles debían aceptarse")
+	}
+	if grade(t, numeric, Case{Expected: "3.14"}, &syndicate.ChatResult{Content: "unos 3.2"}).Passed {
+		t.Error("el número fuera de la tolerancia no debía pasar")
+	}
+	if _, err := numeric.Check(context.Background(), Case{ID: "x", Expected: "pi"}, &syndicate.ChatResult{Content: "3"}); err == nil {
+		t.Error("se esperaba error por caso sin número esperado")
+	}
+}
+
+// TestLLMJudge verifica el juez con rúbrica.
+func TestLLMJudge(t *testing.T) {
+	client := &judgeClient{response: `{"score":6,"reason":"le falta la fuente"}`}
+	judge, err := LLMJudge(client, "judge-model", "Debe citar una fuente.", 0)
+	if err != nil {
+		t.Fatalf("error creando juez: %v", err)
+	}
+
+	g := grade(t, judge, Case{Input: "¿capital de Francia?", Expected: "París"}, &syndicate.ChatResult{Content: "París"})
+	if g.Passed || g.Score != 0.6 || g.Reason != "le falta la fuente" {
+		t.Errorf("veredicto inesperado: %+v", g)
+	}
+	req := client.requests[0]
+	if req.Model != "judge-model" || !strings.Contains(req.Messages[0].Content, "Debe citar una fuente.") ||
+		!strings.Contains(req.Messages[1].Content, "Reference answer:\nParís") || req.ResponseFormat == nil {
+		t.Errorf("request del juez inesperado: %+v", req)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(req.ResponseFormat.JSONSchema.Schema, &schema); err != nil || schema["type"] != "object" {
+		t.Errorf("esquema de respuesta inesperado: %s", req.ResponseFormat.JSONSchema.Schema)
+	}
+
+	if _, err := LLMJudge(client, "m", "r", 11); err == nil {
+		t.Error("se esperaba error por puntaje fuera de rango")
+	}
+}
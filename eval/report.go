@@ -0,0 +1,219 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	syndicate "github.com/Dieg0Code/syndicate-go"
+)
+
+// Report is the outcome of an evaluation run. It can be saved as JSON and compared with a later run.
+type Report struct {
+	Name      string        `json:"name,omitempty"`
+	Dataset   string        `json:"dataset"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+	Summary   Summary       `json:"summary"`
+	Cases     []CaseResult  `json:"cases"`
+}
+
+// CaseResult is the outcome of one case.
+type CaseResult struct {
+	ID        string          `json:"id"`
+	Input     string          `json:"input"`
+	Expected  string          `json:"expected,omitempty"`
+	Output    string          `json:"output"`
+	Passed    bool            `json:"passed"`
+	Error     string          `json:"error,omitempty"` // Set when the target or a grader failed.
+	Grades    []Grade         `json:"grades,omitempty"`
+	ToolCalls []string        `json:"tool_calls,omitempty"`
+	Usage     syndicate.Usage `json:"usage"`
+	Cost      float64         `json:"cost"`
+	Latency   time.Duration   `json:"latency"`
+}
+
+// Summary aggregates the results of a run.
+type Summary struct {
+	Cases    int                      `json:"cases"`
+	Passed   int                      `json:"passed"`
+	Failed   int                      `json:"failed"`  // Cases that ran but did not pass every grader.
+	Errored  int                      `json:"errored"` // Cases whose target or graders failed.
+	PassRate float64                  `json:"pass_rate"`
+	Graders  map[string]GraderSummary `json:"graders,omitempty"`
+	Usage    syndicate.Usage          `json:"usage"`
+	Cost     float64                  `json:"cost"`
+	Latency  LatencySummary           `json:"latency"`
+}
+
+// GraderSummary aggregates the grades given by one grader.
+type GraderSummary struct {
+	Graded    int     `json:"graded"`
+	Passed    int     `json:"passed"`
+	PassRate  float64 `json:"pass_rate"`
+	MeanScore float64 `json:"mean_score"`
+}
+
+// LatencySummary describes the distribution of case latencies.
+type LatencySummary struct {
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P95  time.Duration `json:"p95"`
+	Max  time.Duration `json:"max"`
+}
+
+// summarize aggregates case results.
+func summarize(cases []CaseResult) Summary {
+	s := Summary{Cases: len(cases), Graders: make(map[string]GraderSummary)}
+	latencies := make([]time.Duration, 0, len(cases))
+	var total time.Duration
+	for _, c := range cases {
+		switch {
+		case c.Error != "":
+			s.Errored++
+		case c.Passed:
+			s.Passed++
+		default:
+			s.Failed++
+		}
+		s.Usage = s.Usage.Add(c.Usage)
+		s.Cost += c.Cost
+		latencies = append(latencies, c.Latency)
+		total += c.Latency
+
+		for _, g := range c.Grades {
+			gs := s.Graders[g.Grader]
+			gs.Graded++
+			if g.Passed {
+				gs.Passed++
+			}
+			gs.MeanScore += g.Score
+			s.Graders[g.Grader] = gs
+		}
+	}
+	for name, gs := range s.Graders {
+		gs.PassRate = float64(gs.Passed) / float64(gs.Graded)
+		gs.MeanScore /= float64(gs.Graded)
+		s.Graders[name] = gs
+	}
+	if len(cases) > 0 {
+		s.PassRate = float64(s.Passed) / float64(len(cases))
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		s.Latency = LatencySummary{
+			Mean: total / time.Duration(len(cases)),
+			P50:  percentile(latencies, 0.50),
+			P95:  percentile(latencies, 0.95),
+			Max:  latencies[len(latencies)-1],
+		}
+	}
+	return s
+}
+
+// percentile returns the nearest-rank percentile of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(p*float64(len(sorted))+0.999999) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// Save writes the report to a JSON file.
+func (r *Report) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// LoadReport reads a report saved with Save.
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode report: %w", err)
+	}
+	return &report, nil
+}
+
+// WriteText writes a human-readable summary of the report, listing the cases that did not pass.
+func (r *Report) WriteText(w io.Writer) error {
+	s := r.Summary
+	var b strings.Builder
+	title := r.Dataset
+	if r.Name != "" {
+		title = fmt.Sprintf("%s (%s)", r.Dataset, r.Name)
+	}
+	fmt.Fprintf(&b, "Evaluation of %s\n", title)
+	fmt.Fprintf(&b, "Pass rate: %.1f%% (%d passed, %d failed, %d errored of %d)\n",
+		s.PassRate*100, s.Passed, s.Failed, s.Errored, s.Cases)
+	fmt.Fprintf(&b, "Tokens:    %d (%d prompt, %d completion)\n", s.Usage.TotalTokens, s.Usage.PromptTokens, s.Usage.CompletionTokens)
+	fmt.Fprintf(&b, "Cost:      %.4f\n", s.Cost)
+	fmt.Fprintf(&b, "Latency:   mean %s, p50 %s, p95 %s, max %s\n",
+		round(s.Latency.Mean), round(s.Latency.P50), round(s.Latency.P95), round(s.Latency.Max))
+
+	if len(s.Graders) > 0 {
+		b.WriteString("\n")
+		tw := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "GRADER\tPASS RATE\tMEAN SCORE")
+		for _, name := range sortedKeys(s.Graders) {
+			gs := s.Graders[name]
+			fmt.Fprintf(tw, "%s\t%.1f%%\t%.2f\n", name, gs.PassRate*100, gs.MeanScore)
+		}
+		tw.Flush()
+	}
+
+	var failures []string
+	for _, c := range r.Cases {
+		if !c.Passed {
+			failures = append(failures, fmt.Sprintf("  %s: %s", c.ID, c.failureReason()))
+		}
+	}
+	if len(failures) > 0 {
+		fmt.Fprintf(&b, "\nNot passed:\n%s\n", strings.Join(failures, "\n"))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// failureReason explains why a case did not pass.
+func (c CaseResult) failureReason() string {
+	if c.Error != "" {
+		return "error: " + c.Error
+	}
+	var reasons []string
+	for _, g := range c.Grades {
+		if !g.Passed {
+			reasons = append(reasons, fmt.Sprintf("%s (%s)", g.Grader, g.Reason))
+		}
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// round shortens a duration for display.
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Millisecond)
+}
+
+// sortedKeys returns the keys of a map in alphabetical order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
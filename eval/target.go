@@ -0,0 +1,104 @@
+package eval
+
+import (
+	"context"
+	"time"
+
+	syndicate "github.com/Dieg0Code/syndicate-go"
+)
+
+// defaultUserName is the user name sent for cases that do not set one.
+const defaultUserName = "eval"
+
+// Target is what an evaluation runs each case against.
+type Target interface {
+	Run(ctx context.Context, c Case) (*syndicate.ChatResult, error)
+}
+
+// TargetFunc adapts a function to the Target interface.
+type TargetFunc func(ctx context.Context, c Case) (*syndicate.ChatResult, error)
+
+// Run calls f.
+func (f TargetFunc) Run(ctx context.Context, c Case) (*syndicate.ChatResult, error) {
+	return f(ctx, c)
+}
+
+// AgentTarget runs each case as an ephemeral chat with the agent, in a session of its own, so cases
+// do not see each other and the agent's memory is left untouched. Options are added to every chat.
+// Agents that do not implement syndicate.DetailedAgent report no usage or tool calls.
+//
+// The session of a case is deleted once the case finishes, for agents implementing
+// syndicate.SessionAgent. An agent with a memory factory still calls it for each case's session;
+// the memory it returns is only read.
+func AgentTarget(agent syndicate.Agent, options ...syndicate.ChatOption) Target {
+	return TargetFunc(func(ctx context.Context, c Case) (*syndicate.ChatResult, error) {
+		sessionID := "eval/" + c.ID
+		if sessions, ok := agent.(syndicate.SessionAgent); ok {
+			defer sessions.DeleteSession(sessionID)
+		}
+		chatOptions := append([]syndicate.ChatOption{
+			syndicate.WithUserName(userName(c)),
+			syndicate.WithInput(c.Input),
+			syndicate.WithSessionID(sessionID),
+			syndicate.WithEphemeral(),
+		}, options...)
+
+		if detailed, ok := agent.(syndicate.DetailedAgent); ok {
+			return detailed.ChatDetailed(ctx, chatOptions...)
+		}
+		start := time.Now()
+		content, err := agent.Chat(ctx, chatOptions...)
+		if err != nil {
+			return nil, err
+		}
+		return &syndicate.ChatResult{AgentName: agent.GetName(), Content: content, Duration: time.Since(start)}, nil
+	})
+}
+
+// SyndicateAgentTarget runs each case through the named agent of a syndicate, following handoffs.
+// The syndicate's global history records every case and is shared by them, so later cases can see
+// earlier ones; build a fresh syndicate for each run when cases must be independent.
+// Syndicates that do not implement syndicate.DetailedSyndicate report no usage, tool calls or handoffs.
+func SyndicateAgentTarget(syn syndicate.Syndicate, agentName string, options ...syndicate.ExecuteAgentOption) Target {
+	return TargetFunc(func(ctx context.Context, c Case) (*syndicate.ChatResult, error) {
+		executeOptions := append([]syndicate.ExecuteAgentOption{
+			syndicate.WithExecuteUserName(userName(c)),
+			syndicate.WithExecuteInput(c.Input),
+		}, options...)
+		if detailed, ok := syn.(syndicate.DetailedSyndicate); ok {
+			return detailed.ExecuteAgentDetailed(ctx, agentName, executeOptions...)
+		}
+		start := time.Now()
+		content, err := syn.ExecuteAgent(ctx, agentName, executeOptions...)
+		if err != nil {
+			return nil, err
+		}
+		return &syndicate.ChatResult{AgentName: agentName, Content: content, Duration: time.Since(start)}, nil
+	})
+}
+
+// PipelineTarget runs each case through the syndicate's pipeline. Pipelines only report their
+// final answer, so graders based on usage or tool calls see none.
+func PipelineTarget(syn syndicate.Syndicate, options ...syndicate.PipelineOption) Target {
+	return TargetFunc(func(ctx context.Context, c Case) (*syndicate.ChatResult, error) {
+		pipelineOptions := append([]syndicate.PipelineOption{
+			syndicate.WithPipelineUserName(userName(c)),
+			syndicate.WithPipelineInput(c.Input),
+		}, options...)
+
+		start := time.Now()
+		content, err := syn.ExecutePipeline(ctx, pipelineOptions...)
+		if err != nil {
+			return nil, err
+		}
+		return &syndicate.ChatResult{Content: content, Duration: time.Since(start)}, nil
+	})
+}
+
+// userName returns the user name of a case.
+func userName(c Case) string {
+	if c.UserName != "" {
+		return c.UserName
+	}
+	return defaultUserName
+}
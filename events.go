@@ -0,0 +1,131 @@
+package syndicate
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// EventType identifies the kind of an Event.
+type EventType string
+
+// Event types, in the order they usually happen during a chat turn.
+const (
+	EventTurnStarted      EventType = "turn_started"       // The agent accepted a chat request.
+	EventRequestSent      EventType = "request_sent"       // A request is being sent to the LLM.
+	EventContentDelta     EventType = "content_delta"      // The LLM produced answer text; plans, reasoning and critiques are not reported.
+	EventToolCallStarted  EventType = "tool_call_started"  // A tool is about to run.
+	EventToolCallFinished EventType = "tool_call_finished" // A tool finished running (or was skipped).
+	EventMemoryUpdated    EventType = "memory_updated"     // A message was added to the conversation; for atomic turns, once the turn commits.
+	EventTurnFinished     EventType = "turn_finished"      // The turn completed successfully.
+	EventError            EventType = "error"              // The turn failed.
+)
+
+// Event reports progress of a chat turn, for example to show it in a user interface.
+// Only the fields relevant to the event type are set.
+type Event struct {
+	Type      EventType
+	AgentName string    // Agent running the turn.
+	Time      time.Time // When the event happened.
+
+	Round    int                    // LLM request the event belongs to (EventRequestSent, EventContentDelta).
+	Request  *ChatCompletionRequest // Request sent to the LLM (EventRequestSent).
+	Delta    string                 // New answer text (EventContentDelta).
+	ToolCall *ToolCall              // Tool call (EventToolCallStarted, EventToolCallFinished).
+	Tool     *ToolCallResult        // Outcome of the call, with its duration (EventToolCallFinished).
+	Message  *Message               // Message added to memory (EventMemoryUpdated).
+	Result   *ChatResult            // Result of the turn, with its usage (EventTurnFinished).
+	Err      error                  // Failure of the turn (EventError) or of the tool (EventToolCallFinished).
+}
+
+// EventHandler receives the events of a chat turn. Calls for the same turn never overlap,
+// even when tools run concurrently, so handlers do not need to synchronize; they should
+// return quickly, since the turn waits for them.
+type EventHandler func(ctx context.Context, event Event)
+
+// StreamingLLMClient is implemented by LLM clients that can stream answers.
+// When an agent has event handlers and its client implements this interface, answers are
+// streamed and reported as EventContentDelta events while they are generated.
+type StreamingLLMClient interface {
+	LLMClient
+	// CreateChatCompletionStream sends the request, calls onDelta with each piece of answer text
+	// as it arrives, and returns the complete response once the stream ends.
+	CreateChatCompletionStream(ctx context.Context, req ChatCompletionRequest, onDelta func(delta string)) (ChatCompletionResponse, error)
+}
+
+// WithEventHandler registers a handler for the events of every chat turn of the agent.
+// It can be used multiple times; handlers run in the order they were registered.
+func WithEventHandler(handler EventHandler) AgentOption {
+	return func(a *agent) error {
+		if handler == nil {
+			return errors.New("event handler cannot be nil")
+		}
+		a.eventHandlers = append(a.eventHandlers, handler)
+		return nil
+	}
+}
+
+// WithChatEventHandler registers a handler for the events of this chat request,
+// after the agent's own handlers.
+func WithChatEventHandler(handler EventHandler) ChatOption {
+	return func(r *chatRequest) {
+		if handler != nil {
+			r.eventHandlers = append(r.eventHandlers, handler)
+		}
+	}
+}
+
+// WithExecuteEventHandler registers a handler for the events of the agents run by the execution,
+// including the targets of any handoff.
+func WithExecuteEventHandler(handler EventHandler) ExecuteAgentOption {
+	return func(r *executeAgentRequest) {
+		if handler != nil {
+			r.eventHandlers = append(r.eventHandlers, handler)
+		}
+	}
+}
+
+// WithPipelineEventHandler registers a handler for the events of every agent of the pipeline.
+func WithPipelineEventHandler(handler EventHandler) PipelineOption {
+	return func(r *pipelineRequest) {
+		if handler != nil {
+			r.eventHandlers = append(r.eventHandlers, handler)
+		}
+	}
+}
+
+// eventEmitter delivers the events of a turn to its handlers, one at a time.
+// A nil emitter discards events.
+type eventEmitter struct {
+	agentName string
+	handlers  []EventHandler
+	mutex     sync.Mutex
+}
+
+// newEventEmitter returns an emitter for the given handlers, or nil when there are none.
+func newEventEmitter(agentName string, handlers ...[]EventHandler) *eventEmitter {
+	var all []EventHandler
+	for _, list := range handlers {
+		all = append(all, list...)
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	return &eventEmitter{agentName: agentName, handlers: all}
+}
+
+// emit tags the event with the agent name and the current time and delivers it.
+func (e *eventEmitter) emit(ctx context.Context, event Event) {
+	if e == nil {
+		return
+	}
+	event.AgentName = e.agentName
+	event.Time = time.Now()
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for _, handler := range e.handlers {
+		handler(ctx, event)
+	}
+}
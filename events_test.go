@@ -0,0 +1,294 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// eventRecorder guarda los eventos recibidos.
+type eventRecorder struct {
+	mutex  sync.Mutex
+	events []Event
+}
+
+func (r *eventRecorder) handle(ctx context.Context, event Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.events = append(r.events, event)
+}
+
+// types devuelve los tipos de eventos recibidos, separados por comas.
+func (r *eventRecorder) types() string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	types := make([]string, len(r.events))
+	for i, event := range r.events {
+		types[i] = string(event.Type)
+	}
+	return strings.Join(types, ",")
+}
+
+// deltas devuelve los fragmentos de contenido recibidos, en orden.
+func (r *eventRecorder) deltas() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var deltas []string
+	for _, event := range r.events {
+		if event.Type == EventContentDelta {
+			deltas = append(deltas, event.Delta)
+		}
+	}
+	return deltas
+}
+
+// streamingTestClient devuelve respuestas fijas y reporta su contenido palabra por palabra.
+type streamingTestClient struct {
+	recordingLLMClient
+}
+
+func (c *streamingTestClient) CreateChatCompletionStream(ctx context.Context, req ChatCompletionRequest, onDelta func(string)) (ChatCompletionResponse, error) {
+	resp, err := c.CreateChatCompletion(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	for i, word := range strings.Fields(resp.Choices[0].Message.Content) {
+		if i > 0 {
+			word = " " + word
+		}
+		onDelta(word)
+	}
+	return resp, nil
+}
+
+// TestAgentEvents verifica la secuencia de eventos de un turno con una llamada a herramienta.
+func TestAgentEvents(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "c1", Name: "fakeTool", Args: json.RawMessage(`{}`)}),
+		textResponse("soleado"),
+	}}
+	agentEvents := &eventRecorder{}
+	chatEvents := &eventRecorder{}
+
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("weather"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithTools(&fakeTool{
+			def: ToolDefinition{Name: "fakeTool", Description: "Devuelve el clima", Parameters: json.RawMessage(`{"type":"object"}`)},
+			execFunc: func(args json.RawMessage) (interface{}, error) {
+				return "fake result", nil
+			},
+		}),
+		WithEventHandler(agentEvents.handle),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	result, err := agent.(DetailedAgent).ChatDetailed(context.Background(), WithUserName("user"), WithInput("¿clima?"),
+		WithChatEventHandler(chatEvents.handle))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	// Los turnos son atómicos por defecto: la memoria se reporta cuando el turno confirma sus mensajes.
+	want := "turn_started,request_sent,tool_call_started,tool_call_finished,request_sent,content_delta," +
+		"memory_updated,memory_updated,memory_updated,memory_updated,turn_finished"
+	if got := agentEvents.types(); got != want {
+		t.Errorf("eventos inesperados:\n%s\nse esperaba:\n%s", got, want)
+	}
+	if chatEvents.types() != want {
+		t.Errorf("el handler del chat no recibió los mismos eventos: %s", chatEvents.types())
+	}
+
+	for _, event := range agentEvents.events {
+		if event.AgentName != "weather" || event.Time.IsZero() {
+			t.Errorf("evento sin agente o sin hora: %+v", event)
+		}
+		switch event.Type {
+		case EventRequestSent:
+			if event.Round == 0 || event.Request == nil {
+				t.Errorf("request_sent incompleto: %+v", event)
+			}
+		case EventToolCallFinished:
+			if event.ToolCall.Name != "fakeTool" || event.Tool == nil || event.Tool.Result != `"fake result"` {
+				t.Errorf("tool_call_finished inesperado: %+v", event)
+			}
+		case EventContentDelta:
+			if event.Delta != "soleado" || event.Round != 2 {
+				t.Errorf("content_delta inesperado: %+v", event)
+			}
+		case EventTurnFinished:
+			if event.Result != result {
+				t.Errorf("turn_finished no incluye el resultado del turno")
+			}
+		}
+	}
+}
+
+// TestContentDeltasOnlyAnswer verifica que el plan, los pasos y el razonamiento no se reporten como contenido.
+func TestContentDeltasOnlyAnswer(t *testing.T) {
+	planClient := &streamingTestClient{recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse(`{"steps":["buscar el precio"]}`),
+		textResponse("el precio es 10"),
+		textResponse("Debes pagar 10"),
+	}}}
+	planEvents := &eventRecorder{}
+	agent, err := NewAgent(
+		WithClient(planClient),
+		WithName("planner"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithStrategy(PlanAndExecuteStrategy(0)),
+		WithEventHandler(planEvents.handle),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("¿cuánto pago?")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if got := strings.Join(planEvents.deltas(), ""); got != "Debes pagar 10" {
+		t.Errorf("fragmentos inesperados con plan y ejecución: %q", got)
+	}
+
+	reactClient := &streamingTestClient{recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse("Thought: ya lo sé\nFinal Answer: son las diez"),
+	}}}
+	reactEvents := &eventRecorder{}
+	agent, err = NewAgent(
+		WithClient(reactClient),
+		WithName("react"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithStrategy(ReActStrategy(0)),
+		WithEventHandler(reactEvents.handle),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("¿qué hora es?")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if got := reactEvents.deltas(); len(got) != 1 || got[0] != "son las diez" {
+		t.Errorf("fragmentos inesperados con ReAct: %q", got)
+	}
+}
+
+// TestMemoryUpdatedEvents verifica que memory_updated solo se emita por escrituras que persisten.
+func TestMemoryUpdatedEvents(t *testing.T) {
+	countMemoryEvents := func(r *eventRecorder) int {
+		return strings.Count(r.types(), string(EventMemoryUpdated))
+	}
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("hola"), textResponse("efímero")}}
+	events := &eventRecorder{}
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("atomic"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithAtomicTurns(true),
+		WithEventHandler(events.handle),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := agent.Chat(ctx, WithUserName("user"), WithInput("hola")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	want := "turn_started,request_sent,content_delta,memory_updated,memory_updated,turn_finished"
+	if got := events.types(); got != want {
+		t.Errorf("eventos inesperados:\n%s\nse esperaba:\n%s", got, want)
+	}
+
+	ephemeral := &eventRecorder{}
+	if _, err := agent.Chat(ctx, WithUserName("user"), WithInput("prueba"), WithEphemeral(), WithChatEventHandler(ephemeral.handle)); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	dryRun := &eventRecorder{}
+	if _, err := agent.(DetailedAgent).ChatDetailed(ctx, WithUserName("user"), WithInput("prueba"), WithDryRun(), WithChatEventHandler(dryRun.handle)); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	failed := &eventRecorder{}
+	if _, err := agent.Chat(ctx, WithUserName("user"), WithInput("falla"), WithChatEventHandler(failed.handle)); err == nil {
+		t.Fatal("se esperaba que el turno fallara")
+	}
+	for name, recorder := range map[string]*eventRecorder{"efímero": ephemeral, "dry run": dryRun, "revertido": failed} {
+		if n := countMemoryEvents(recorder); n != 0 {
+			t.Errorf("el turno %s emitió %d eventos memory_updated: %s", name, n, recorder.types())
+		}
+	}
+}
+
+// TestAgentEventsStreamingAndError verifica los fragmentos con un cliente con streaming y el evento de error.
+func TestAgentEventsStreamingAndError(t *testing.T) {
+	client := &streamingTestClient{recordingLLMClient{responses: []ChatCompletionResponse{textResponse("hace mucho sol")}}}
+	events := &eventRecorder{}
+	agent, _ := NewAgent(
+		WithClient(client),
+		WithName("weather"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithEventHandler(events.handle),
+	)
+
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("¿clima?")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	deltas := events.deltas()
+	if strings.Join(deltas, "") != "hace mucho sol" || len(deltas) != 3 {
+		t.Errorf("fragmentos inesperados: %q", deltas)
+	}
+
+	failing := &eventRecorder{}
+	agent, _ = NewAgent(
+		WithClient(&fakeLLMClientWithError{}),
+		WithName("broken"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+	)
+	_, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("hola"), WithChatEventHandler(failing.handle))
+	if err == nil {
+		t.Fatal("se esperaba error")
+	}
+	last := failing.events[len(failing.events)-1]
+	if last.Type != EventError || !errors.Is(last.Err, err) {
+		t.Errorf("se esperaba un evento de error al final, se obtuvo %+v", last)
+	}
+}
+
+// TestPipelineEvents verifica que los eventos del pipeline identifiquen a cada agente.
+func TestPipelineEvents(t *testing.T) {
+	first := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("borrador")}}
+	second := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("final")}}
+	syn, err := NewSyndicate(
+		WithAgents(newHandoffTestAgent(t, "writer", first), newHandoffTestAgent(t, "editor", second)),
+		WithPipeline("writer", "editor"),
+	)
+	if err != nil {
+		t.Fatalf("error creando sindicato: %v", err)
+	}
+
+	events := &eventRecorder{}
+	if _, err := syn.ExecutePipeline(context.Background(), WithPipelineUserName("user"), WithPipelineInput("escribe"),
+		WithPipelineEventHandler(events.handle)); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	var finished []string
+	for _, event := range events.events {
+		if event.Type == EventTurnFinished {
+			finished = append(finished, event.AgentName+"="+event.Result.Content)
+		}
+	}
+	if strings.Join(finished, ",") != "writer=borrador,editor=final" {
+		t.Errorf("turnos terminados inesperados: %v", finished)
+	}
+}
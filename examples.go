@@ -0,0 +1,259 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"unicode/utf8"
+)
+
+// defaultMaxExamples is how many examples a similarity selector returns when no limit is set.
+const defaultMaxExamples = 3
+
+// Example is a curated exchange shown to the model before the conversation: the user's input,
+// the tool calls the assistant made to answer it, if any, and the ideal answer.
+type Example struct {
+	Input     string            // The user's message.
+	ToolCalls []ExampleToolCall // Tool calls made before answering, in order.
+	Output    string            // The ideal answer.
+}
+
+// ExampleToolCall is a tool call made in an Example, with the result the tool returned.
+type ExampleToolCall struct {
+	Name   string          // Name of the tool.
+	Args   json.RawMessage // Arguments passed to the tool.
+	Result string          // Result sent back to the model.
+}
+
+// ExampleSelector chooses the examples shown to the model for a chat turn, given the user's input.
+type ExampleSelector interface {
+	SelectExamples(ctx context.Context, input string) ([]Example, error)
+}
+
+// ExampleSelectorFunc adapts a function to the ExampleSelector interface.
+type ExampleSelectorFunc func(ctx context.Context, input string) ([]Example, error)
+
+// SelectExamples calls f.
+func (f ExampleSelectorFunc) SelectExamples(ctx context.Context, input string) ([]Example, error) {
+	return f(ctx, input)
+}
+
+// ExampleLimit caps the examples chosen by a similarity selector. A zero field sets no cap on
+// that dimension; when both are zero, at most 3 examples are chosen.
+type ExampleLimit struct {
+	Count  int // Maximum number of examples.
+	Tokens int // Maximum estimated tokens of the selected examples.
+}
+
+// WithExamples shows the given examples to the model on every chat turn, between the system
+// prompt and the conversation history. It replaces any selector set with WithExampleSelector.
+func WithExamples(examples ...Example) AgentOption {
+	return func(a *agent) error {
+		if err := validateExamples(examples); err != nil {
+			return err
+		}
+		examples = append([]Example(nil), examples...)
+		a.examples = ExampleSelectorFunc(func(ctx context.Context, input string) ([]Example, error) {
+			return examples, nil
+		})
+		return nil
+	}
+}
+
+// WithExampleSelector sets how the examples shown to the model are chosen on each chat turn,
+// such as a selector created with NewSimilarityExampleSelector. It replaces WithExamples.
+func WithExampleSelector(selector ExampleSelector) AgentOption {
+	return func(a *agent) error {
+		if selector == nil {
+			return errors.New("example selector cannot be nil")
+		}
+		a.examples = selector
+		return nil
+	}
+}
+
+// similarityExampleSelector implements NewSimilarityExampleSelector.
+type similarityExampleSelector struct {
+	embed    EmbeddingFunc
+	examples []Example
+	limit    ExampleLimit
+	mutex    sync.Mutex
+	vectors  [][]float32 // Embeddings of the example inputs; computed on first use.
+}
+
+// NewSimilarityExampleSelector returns a selector that chooses the examples whose input is most
+// similar to the user's input, by cosine similarity of their embeddings, within limit. Examples
+// that would exceed the token cap are skipped. The chosen examples are ordered from least to most
+// similar, so the closest one sits right before the conversation.
+//
+// The example inputs are embedded on first use; the user's input is embedded on every turn.
+func NewSimilarityExampleSelector(embed EmbeddingFunc, examples []Example, limit ExampleLimit) (ExampleSelector, error) {
+	if embed == nil {
+		return nil, errors.New("embedding function cannot be nil")
+	}
+	if len(examples) == 0 {
+		return nil, errors.New("at least one example is required")
+	}
+	if limit.Count < 0 || limit.Tokens < 0 {
+		return nil, errors.New("example limits cannot be negative")
+	}
+	if err := validateExamples(examples); err != nil {
+		return nil, err
+	}
+	if limit.Count == 0 && limit.Tokens == 0 {
+		limit.Count = defaultMaxExamples
+	}
+	return &similarityExampleSelector{
+		embed:    embed,
+		examples: append([]Example(nil), examples...),
+		limit:    limit,
+	}, nil
+}
+
+func (s *similarityExampleSelector) SelectExamples(ctx context.Context, input string) ([]Example, error) {
+	vectors, err := s.exampleVectors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	query, err := s.embed(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed input: %w", err)
+	}
+
+	order := make([]int, len(s.examples))
+	scores := make([]float64, len(s.examples))
+	for i := range s.examples {
+		order[i] = i
+		scores[i] = cosineSimilarity(query, vectors[i])
+	}
+	sort.SliceStable(order, func(i, j int) bool { return scores[order[i]] > scores[order[j]] })
+
+	var selected []Example
+	tokens := 0
+	for _, i := range order {
+		if s.limit.Count > 0 && len(selected) == s.limit.Count {
+			break
+		}
+		size := s.examples[i].estimateTokens()
+		if s.limit.Tokens > 0 && tokens+size > s.limit.Tokens {
+			continue
+		}
+		selected = append(selected, s.examples[i])
+		tokens += size
+	}
+
+	// Least similar first, so the closest example is the nearest to the conversation.
+	for i, j := 0, len(selected)-1; i < j; i, j = i+1, j-1 {
+		selected[i], selected[j] = selected[j], selected[i]
+	}
+	return selected, nil
+}
+
+// exampleVectors returns the embeddings of the example inputs, computing them on first use.
+func (s *similarityExampleSelector) exampleVectors(ctx context.Context) ([][]float32, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.vectors != nil {
+		return s.vectors, nil
+	}
+	vectors := make([][]float32, len(s.examples))
+	for i, example := range s.examples {
+		vector, err := s.embed(ctx, example.Input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed example %d: %w", i, err)
+		}
+		vectors[i] = vector
+	}
+	s.vectors = vectors
+	return vectors, nil
+}
+
+// cosineSimilarity returns the cosine similarity of two vectors, or 0 when it is undefined.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// validateExamples checks that every example has an input, an answer and named tool calls.
+func validateExamples(examples []Example) error {
+	for i, example := range examples {
+		if example.Input == "" || example.Output == "" {
+			return fmt.Errorf("example %d: input and output are required", i)
+		}
+		for _, call := range example.ToolCalls {
+			if call.Name == "" {
+				return fmt.Errorf("example %d: tool call name cannot be empty", i)
+			}
+		}
+	}
+	return nil
+}
+
+// estimateTokens roughly estimates the tokens of the example, at about four characters per token.
+func (e Example) estimateTokens() int {
+	chars := utf8.RuneCountInString(e.Input) + utf8.RuneCountInString(e.Output)
+	for _, call := range e.ToolCalls {
+		chars += utf8.RuneCountInString(call.Name) + len(call.Args) + utf8.RuneCountInString(call.Result)
+	}
+	return (chars + 3) / 4
+}
+
+// exampleMessages converts examples into the messages placed before the conversation.
+// Tool call IDs are numbered per example so they never collide with real ones.
+func exampleMessages(examples []Example) []Message {
+	var msgs []Message
+	for i, example := range examples {
+		msgs = append(msgs, Message{Role: RoleUser, Content: example.Input})
+		if len(example.ToolCalls) > 0 {
+			calls := make([]ToolCall, len(example.ToolCalls))
+			for j, call := range example.ToolCalls {
+				args := call.Args
+				if len(args) == 0 {
+					args = json.RawMessage("{}")
+				}
+				calls[j] = ToolCall{ID: fmt.Sprintf("example_%d_%d", i+1, j+1), Name: call.Name, Args: args}
+			}
+			msgs = append(msgs, Message{Role: RoleAssistant, ToolCalls: calls})
+			for j, call := range example.ToolCalls {
+				msgs = append(msgs, Message{
+					Role:       RoleTool,
+					Content:    call.Result,
+					Name:       call.Name,
+					ToolCallID: calls[j].ID,
+				})
+			}
+		}
+		msgs = append(msgs, Message{Role: RoleAssistant, Content: example.Output})
+	}
+	return msgs
+}
+
+// selectExamples returns the example messages for a turn with the given input.
+func (a *agent) selectExamples(ctx context.Context, input string) ([]Message, error) {
+	a.mutex.RLock()
+	selector := a.examples
+	a.mutex.RUnlock()
+	if selector == nil {
+		return nil, nil
+	}
+	examples, err := selector.SelectExamples(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("example selection failed: %w", err)
+	}
+	return exampleMessages(examples), nil
+}
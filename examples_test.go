@@ -0,0 +1,157 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// keywordEmbedding genera vectores según las palabras clave presentes en el texto y cuenta las llamadas.
+func keywordEmbedding(calls *atomic.Int32) EmbeddingFunc {
+	keywords := []string{"clima", "lluvia", "factura", "pago"}
+	return func(ctx context.Context, text string) ([]float32, error) {
+		calls.Add(1)
+		vector := make([]float32, len(keywords))
+		for i, keyword := range keywords {
+			if strings.Contains(text, keyword) {
+				vector[i] = 1
+			}
+		}
+		return vector, nil
+	}
+}
+
+// TestWithExamples verifica que los ejemplos se inserten entre el prompt de sistema y el historial
+// sin guardarse en memoria.
+func TestWithExamples(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("Soleado")}}
+	memory := NewSimpleMemory()
+	memory.Add(Message{Role: RoleUser, Content: "previo"})
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("Agente"),
+		WithSystemPrompt("Eres un asistente."),
+		WithMemory(memory),
+		WithModel(openai.GPT4),
+		WithCacheBreakpoints(),
+		WithExamples(Example{
+			Input:     "¿Clima en Lima?",
+			ToolCalls: []ExampleToolCall{{Name: "clima", Args: json.RawMessage(`{"ciudad":"Lima"}`), Result: `"nublado"`}},
+			Output:    "Está nublado en Lima.",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("¿Clima en Quito?")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	messages := client.Requests()[0].Messages
+	var roles []string
+	for _, msg := range messages {
+		roles = append(roles, msg.Role)
+	}
+	if strings.Join(roles, ",") != "system,user,assistant,tool,assistant,user,user" {
+		t.Fatalf("secuencia de mensajes inesperada: %v", roles)
+	}
+	call := messages[2].ToolCalls[0]
+	if call.Name != "clima" || messages[3].ToolCallID != call.ID || messages[3].Content != `"nublado"` {
+		t.Errorf("llamada de ejemplo inesperada: %+v %+v", call, messages[3])
+	}
+	if messages[4].Content != "Está nublado en Lima." || !messages[4].CacheBreakpoint {
+		t.Errorf("respuesta de ejemplo inesperada: %+v", messages[4])
+	}
+	if len(memory.Get()) != 3 {
+		t.Errorf("los ejemplos no deben guardarse en memoria: %+v", memory.Get())
+	}
+}
+
+// TestSimilarityExampleSelector verifica la selección por similitud, los límites y el orden.
+func TestSimilarityExampleSelector(t *testing.T) {
+	examples := []Example{
+		{Input: "pregunta de factura", Output: "respuesta de factura"},
+		{Input: "pregunta de clima y lluvia", Output: "respuesta de clima"},
+		{Input: "pregunta de pago y factura", Output: "respuesta de pago"},
+		{Input: "pregunta de lluvia", Output: strings.Repeat("larga ", 100)},
+	}
+
+	var calls atomic.Int32
+	selector, err := NewSimilarityExampleSelector(keywordEmbedding(&calls), examples, ExampleLimit{Count: 2})
+	if err != nil {
+		t.Fatalf("error creando selector: %v", err)
+	}
+	selected, err := selector.SelectExamples(context.Background(), "duda con mi factura")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if len(selected) != 2 || selected[0].Input != examples[2].Input || selected[1].Input != examples[0].Input {
+		t.Errorf("selección inesperada: %+v", selected)
+	}
+	if _, err := selector.SelectExamples(context.Background(), "otra factura"); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if calls.Load() != 6 {
+		t.Errorf("los ejemplos debían embeberse una sola vez, llamadas: %d", calls.Load())
+	}
+
+	selector, err = NewSimilarityExampleSelector(keywordEmbedding(&calls), examples, ExampleLimit{Tokens: 20})
+	if err != nil {
+		t.Fatalf("error creando selector: %v", err)
+	}
+	selected, err = selector.SelectExamples(context.Background(), "lluvia")
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	for _, example := range selected {
+		if example.Input == examples[3].Input {
+			t.Errorf("el ejemplo que excede el límite de tokens no debía elegirse: %+v", selected)
+		}
+	}
+	if len(selected) == 0 || selected[len(selected)-1].Input != examples[1].Input {
+		t.Errorf("el ejemplo más similar que cabe debía ir al final: %+v", selected)
+	}
+}
+
+// TestExampleValidation verifica los errores de configuración y de selección.
+func TestExampleValidation(t *testing.T) {
+	var calls atomic.Int32
+	embed := keywordEmbedding(&calls)
+	valid := []Example{{Input: "hola", Output: "chao"}}
+
+	if _, err := NewAgent(WithExamples(Example{Input: "hola"})); err == nil {
+		t.Error("se esperaba error por ejemplo sin respuesta")
+	}
+	if _, err := NewSimilarityExampleSelector(nil, valid, ExampleLimit{}); err == nil {
+		t.Error("se esperaba error por función de embeddings nula")
+	}
+	if _, err := NewSimilarityExampleSelector(embed, nil, ExampleLimit{}); err == nil {
+		t.Error("se esperaba error por banco de ejemplos vacío")
+	}
+	if _, err := NewSimilarityExampleSelector(embed, valid, ExampleLimit{Count: -1}); err == nil {
+		t.Error("se esperaba error por límite negativo")
+	}
+
+	failing := ExampleSelectorFunc(func(ctx context.Context, input string) ([]Example, error) {
+		return nil, errors.New("sin conexión")
+	})
+	agent, err := NewAgent(
+		WithClient(&recordingLLMClient{}),
+		WithName("Agente"),
+		WithMemory(NewSimpleMemory()),
+		WithModel(openai.GPT4),
+		WithExampleSelector(failing),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("hola")); err == nil || !strings.Contains(err.Error(), "sin conexión") {
+		t.Errorf("se esperaba el error del selector, se obtuvo: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ go 1.24.0
 require (
 	github.com/cohesion-org/deepseek-go v1.2.3
 	github.com/sashabaranov/go-openai v1.37.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require github.com/joho/godotenv v1.5.1 // indirect
@@ -0,0 +1,123 @@
+package syndicate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrMaxHandoffs is returned when a conversation is handed off more times than the syndicate allows.
+var ErrMaxHandoffs = errors.New("maximum number of handoffs exceeded")
+
+// defaultMaxHandoffs is the number of handoffs a single execution may follow unless WithMaxHandoffs is used.
+const defaultMaxHandoffs = 5
+
+// Handoff transfers a conversation from one agent to another.
+// A tool that returns a Handoff ends the calling agent's chat turn without a final answer;
+// the Syndicate then continues the conversation with the target agent.
+type Handoff struct {
+	From   string `json:"from"`   // Agent that handed the conversation off; filled in by the agent.
+	Target string `json:"target"` // Agent that takes over the conversation.
+	Note   string `json:"note"`   // Context for the target agent, written by the model.
+}
+
+// handoffInput is the argument schema of the generated handoff tools.
+type handoffInput struct {
+	Note string `json:"note" description:"What the next agent needs to know: the user's request and anything already established"`
+}
+
+// handoffTool transfers the conversation to a fixed target agent.
+type handoffTool struct {
+	target      string
+	description string
+	schema      json.RawMessage
+}
+
+// newHandoffTool creates the transfer tool for the given target agent.
+func newHandoffTool(target Agent) (*handoffTool, error) {
+	schema, err := GenerateRawSchema(handoffInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate schema: %w", err)
+	}
+
+	description := fmt.Sprintf("Transfers the conversation to the %s agent when it is better suited to handle the request.", target.GetName())
+	if described, ok := target.(interface{ GetDescription() string }); ok && described.GetDescription() != "" {
+		description += " " + described.GetDescription()
+	}
+
+	return &handoffTool{target: target.GetName(), description: description, schema: schema}, nil
+}
+
+// handoffToolName returns the name of the tool that transfers the conversation to the given agent.
+func handoffToolName(agentName string) string {
+	return "transfer_to_" + invalidToolNameChars.ReplaceAllString(agentName, "_")
+}
+
+func (t *handoffTool) GetDefinition() ToolDefinition {
+	return ToolDefinition{
+		Name:        handoffToolName(t.target),
+		Description: t.description,
+		Parameters:  t.schema,
+	}
+}
+
+func (t *handoffTool) Execute(args json.RawMessage) (interface{}, error) {
+	var input handoffInput
+	if err := json.Unmarshal(args, &input); err != nil {
+		return nil, fmt.Errorf("invalid arguments for handoff to %s: %w", t.target, err)
+	}
+	return Handoff{Target: t.target, Note: input.Note}, nil
+}
+
+// WithHandoffs allows the agent named from to transfer the conversation to the agents named in to.
+// A transfer_to_<agent> tool is added to from's chats for each target when it is executed by the syndicate.
+// All agents must be registered before this option is applied.
+func WithHandoffs(from string, to ...string) SyndicateOption {
+	return func(s *syndicate) error {
+		if _, exists := s.agents[from]; !exists {
+			return fmt.Errorf("agent %s not found in syndicate", from)
+		}
+		if len(to) == 0 {
+			return fmt.Errorf("handoff targets for agent %s cannot be empty", from)
+		}
+		for _, name := range to {
+			if name == from {
+				return fmt.Errorf("agent %s cannot hand off to itself", from)
+			}
+			target, exists := s.agents[name]
+			if !exists {
+				return fmt.Errorf("agent %s not found in syndicate", name)
+			}
+			tool, err := newHandoffTool(target)
+			if err != nil {
+				return err
+			}
+			s.handoffs[from] = append(s.handoffs[from], tool)
+		}
+		return nil
+	}
+}
+
+// WithMaxHandoffs sets how many handoffs a single agent execution may follow before failing with ErrMaxHandoffs.
+func WithMaxHandoffs(max int) SyndicateOption {
+	return func(s *syndicate) error {
+		if max < 1 {
+			return fmt.Errorf("max handoffs must be at least 1")
+		}
+		s.maxHandoffs = max
+		return nil
+	}
+}
+
+// handoffMessage renders a handoff as a message the target agent can read.
+func handoffMessage(handoff Handoff) Message {
+	content := fmt.Sprintf("[%s]: Transferred the conversation to %s.", handoff.From, handoff.Target)
+	if handoff.Note != "" {
+		content += " Note: " + handoff.Note
+	}
+	return Message{
+		Role:    RoleAssistant,
+		Content: content,
+		Name:    handoff.From,
+	}
+}
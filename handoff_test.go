@@ -0,0 +1,151 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// newHandoffTestAgent crea un agente con un cliente que devuelve las respuestas indicadas.
+func newHandoffTestAgent(t *testing.T, name string, client LLMClient) Agent {
+	t.Helper()
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName(name),
+		WithDescription("Handles "+name+" requests."),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente %s: %v", name, err)
+	}
+	return agent
+}
+
+// TestSyndicateHandoff verifica que el control pase al agente destino con la nota y que el resultado lo identifique.
+func TestSyndicateHandoff(t *testing.T) {
+	triageResponse := toolCallResponse(ToolCall{ID: "h1", Name: "transfer_to_billing", Args: json.RawMessage(`{"note":"el usuario pide su factura"}`)})
+	triageResponse.Usage = Usage{TotalTokens: 10}
+	triageClient := &recordingLLMClient{responses: []ChatCompletionResponse{triageResponse}}
+	billingResponse := textResponse("Factura enviada")
+	billingResponse.Usage = Usage{TotalTokens: 20}
+	billingClient := &recordingLLMClient{responses: []ChatCompletionResponse{billingResponse}}
+
+	syn, err := NewSyndicate(
+		WithAgents(newHandoffTestAgent(t, "triage", triageClient), newHandoffTestAgent(t, "billing", billingClient)),
+		WithHandoffs("triage", "billing"),
+	)
+	if err != nil {
+		t.Fatalf("error creando syndicate: %v", err)
+	}
+
+	result, err := syn.(DetailedSyndicate).ExecuteAgentDetailed(context.Background(), "triage",
+		WithExecuteUserName("user"), WithExecuteInput("quiero mi factura"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if result.AgentName != "billing" || result.Content != "Factura enviada" {
+		t.Errorf("resultado inesperado: agente=%s contenido=%s", result.AgentName, result.Content)
+	}
+	if len(result.Handoffs) != 1 || result.Handoffs[0].From != "triage" || result.Handoffs[0].Target != "billing" {
+		t.Errorf("handoffs inesperados: %+v", result.Handoffs)
+	}
+	if result.Usage.TotalTokens != 30 {
+		t.Errorf("se esperaba el uso acumulado de ambos agentes, se obtuvo %d", result.Usage.TotalTokens)
+	}
+
+	tools := triageClient.Requests()[0].Tools
+	if len(tools) != 1 || tools[0].Name != "transfer_to_billing" || !strings.Contains(tools[0].Description, "Handles billing requests.") {
+		t.Errorf("herramientas de handoff inesperadas: %+v", tools)
+	}
+	if len(billingClient.Requests()[0].Tools) != 0 {
+		t.Error("billing no debía recibir herramientas de handoff")
+	}
+
+	msgs := billingClient.Requests()[0].Messages
+	if last := msgs[len(msgs)-1]; !strings.Contains(last.Content, "el usuario pide su factura") {
+		t.Errorf("billing no recibió la nota del handoff: %+v", last)
+	}
+
+	history := syn.GetGlobalHistory()
+	if len(history) != 3 || history[2].Content != "[billing]: Factura enviada" {
+		t.Errorf("historial global inesperado: %+v", history)
+	}
+}
+
+// TestSyndicateMaxHandoffs verifica que un ciclo de handoffs se corte con ErrMaxHandoffs.
+func TestSyndicateMaxHandoffs(t *testing.T) {
+	ping := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "a", Name: "transfer_to_pong", Args: json.RawMessage(`{"note":""}`)}),
+		toolCallResponse(ToolCall{ID: "b", Name: "transfer_to_pong", Args: json.RawMessage(`{"note":""}`)}),
+	}}
+	pong := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "c", Name: "transfer_to_ping", Args: json.RawMessage(`{"note":""}`)}),
+	}}
+
+	syn, err := NewSyndicate(
+		WithAgents(newHandoffTestAgent(t, "ping", ping), newHandoffTestAgent(t, "pong", pong)),
+		WithHandoffs("ping", "pong"),
+		WithHandoffs("pong", "ping"),
+		WithMaxHandoffs(2),
+	)
+	if err != nil {
+		t.Fatalf("error creando syndicate: %v", err)
+	}
+
+	_, err = syn.ExecuteAgent(context.Background(), "ping", WithExecuteUserName("user"), WithExecuteInput("hola"))
+	if !errors.Is(err, ErrMaxHandoffs) {
+		t.Errorf("se esperaba ErrMaxHandoffs, se obtuvo: %v", err)
+	}
+}
+
+// TestWithHandoffsValidation verifica la validación de la configuración de handoffs.
+func TestWithHandoffsValidation(t *testing.T) {
+	a := newHandoffTestAgent(t, "a", &recordingLLMClient{})
+
+	cases := map[string][]SyndicateOption{
+		"origen inexistente":  {WithAgents(a), WithHandoffs("x", "a")},
+		"destino inexistente": {WithAgents(a), WithHandoffs("a", "x")},
+		"sin destinos":        {WithAgents(a), WithHandoffs("a")},
+		"a sí mismo":          {WithAgents(a), WithHandoffs("a", "a")},
+		"máximo inválido":     {WithMaxHandoffs(0)},
+	}
+	for name, options := range cases {
+		if _, err := NewSyndicate(options...); err == nil {
+			t.Errorf("%s: se esperaba un error", name)
+		}
+	}
+}
+
+// TestWithExtraTools verifica que las herramientas extra solo estén disponibles en la llamada indicada.
+func TestWithExtraTools(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "c1", Name: "extra", Args: json.RawMessage(`{}`)}),
+		textResponse("listo"),
+		textResponse("sin herramientas"),
+	}}
+	agent := newHandoffTestAgent(t, "agent", client)
+	executed := false
+	extra := &fakeTool{
+		def: ToolDefinition{Name: "extra"},
+		execFunc: func(args json.RawMessage) (interface{}, error) {
+			executed = true
+			return "ok", nil
+		},
+	}
+
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("hola"), WithExtraTools(extra)); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if !executed {
+		t.Error("la herramienta extra no se ejecutó")
+	}
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("otra vez")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if tools := client.Requests()[2].Tools; len(tools) != 0 {
+		t.Errorf("la herramienta extra no debía persistir: %+v", tools)
+	}
+}
@@ -0,0 +1,111 @@
+package syndicate
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSkipToolCall can be returned (or wrapped) by a BeforeToolCall hook to veto a tool call
+// without aborting the chat. The tool is not executed and the error message is sent back to
+// the model as the tool result, so it can react to the refusal.
+var ErrSkipToolCall = errors.New("tool call skipped")
+
+// Hooks groups optional callbacks invoked around each stage of an agent's chat turn.
+// Any field may be left nil. Hooks registered with WithHooks run in registration order,
+// and the first error returned by a hook aborts the turn (see ErrSkipToolCall for the exception).
+// Tool hooks may run concurrently when the model requests several tool calls at once.
+type Hooks struct {
+	// BeforeRequest runs before each request is sent to the LLM and may modify it.
+	BeforeRequest func(ctx context.Context, req *ChatCompletionRequest) error
+	// AfterResponse runs after each response is received from the LLM and may modify it.
+	AfterResponse func(ctx context.Context, resp *ChatCompletionResponse) error
+	// BeforeToolCall runs before a tool is executed and may rewrite the call arguments.
+	// Returning an error vetoes the call.
+	BeforeToolCall func(ctx context.Context, call *ToolCall) error
+	// AfterToolCall runs after a tool is executed with its serialized result and execution error.
+	// It may rewrite the result; the returned error replaces the execution error.
+	AfterToolCall func(ctx context.Context, call ToolCall, result *string, err error) error
+	// BeforeMemoryWrite runs before a message is stored in the agent's memory and may modify it.
+	BeforeMemoryWrite func(ctx context.Context, msg *Message) error
+	// OnError is notified of any error that ends a chat turn.
+	OnError func(ctx context.Context, err error)
+}
+
+// hookChain composes several Hooks so that each stage runs them in order.
+type hookChain []Hooks
+
+// beforeRequest runs every BeforeRequest hook in order.
+func (c hookChain) beforeRequest(ctx context.Context, req *ChatCompletionRequest) error {
+	for _, h := range c {
+		if h.BeforeRequest != nil {
+			if err := h.BeforeRequest(ctx, req); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// afterResponse runs every AfterResponse hook in order.
+func (c hookChain) afterResponse(ctx context.Context, resp *ChatCompletionResponse) error {
+	for _, h := range c {
+		if h.AfterResponse != nil {
+			if err := h.AfterResponse(ctx, resp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// beforeToolCall runs every BeforeToolCall hook in order.
+func (c hookChain) beforeToolCall(ctx context.Context, call *ToolCall) error {
+	for _, h := range c {
+		if h.BeforeToolCall != nil {
+			if err := h.BeforeToolCall(ctx, call); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// afterToolCall runs every AfterToolCall hook in order, feeding each one the error left by the previous.
+func (c hookChain) afterToolCall(ctx context.Context, call ToolCall, result *string, err error) error {
+	for _, h := range c {
+		if h.AfterToolCall != nil {
+			err = h.AfterToolCall(ctx, call, result, err)
+		}
+	}
+	return err
+}
+
+// beforeMemoryWrite runs every BeforeMemoryWrite hook in order.
+func (c hookChain) beforeMemoryWrite(ctx context.Context, msg *Message) error {
+	for _, h := range c {
+		if h.BeforeMemoryWrite != nil {
+			if err := h.BeforeMemoryWrite(ctx, msg); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// onError notifies every OnError hook.
+func (c hookChain) onError(ctx context.Context, err error) {
+	for _, h := range c {
+		if h.OnError != nil {
+			h.OnError(ctx, err)
+		}
+	}
+}
+
+// WithHooks registers lifecycle hooks on the agent.
+// It can be used multiple times; hooks run in the order they were registered.
+func WithHooks(hooks ...Hooks) AgentOption {
+	return func(a *agent) error {
+		a.hooks = append(a.hooks, hooks...)
+		return nil
+	}
+}
@@ -0,0 +1,232 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingLLMClient devuelve respuestas predefinidas y registra cada request recibido.
+type recordingLLMClient struct {
+	mu        sync.Mutex
+	responses []ChatCompletionResponse
+	requests  []ChatCompletionRequest
+}
+
+func (c *recordingLLMClient) CreateChatCompletion(ctx context.Context, req ChatCompletionRequest) (ChatCompletionResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	if len(c.requests) > len(c.responses) {
+		return ChatCompletionResponse{}, errors.New("no hay más respuestas configuradas")
+	}
+	return c.responses[len(c.requests)-1], nil
+}
+
+func (c *recordingLLMClient) Requests() []ChatCompletionRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ChatCompletionRequest(nil), c.requests...)
+}
+
+// textResponse construye una respuesta simple con el contenido indicado.
+func textResponse(content string) ChatCompletionResponse {
+	return ChatCompletionResponse{
+		Choices: []Choice{{Message: Message{Role: RoleAssistant, Content: content}, FinishReason: FinishReasonStop}},
+	}
+}
+
+// toolCallResponse construye una respuesta que solicita las llamadas a herramientas indicadas.
+func toolCallResponse(calls ...ToolCall) ChatCompletionResponse {
+	return ChatCompletionResponse{
+		Choices: []Choice{{Message: Message{Role: RoleAssistant, ToolCalls: calls}, FinishReason: FinishReasonToolCalls}},
+	}
+}
+
+// TestHooksRequestAndResponse verifica que los hooks puedan modificar el request y la respuesta, en orden.
+func TestHooksRequestAndResponse(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("hola")}}
+	var order []string
+
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("hookAgent"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithHooks(Hooks{
+			BeforeRequest: func(ctx context.Context, req *ChatCompletionRequest) error {
+				order = append(order, "first")
+				req.Temperature = 0.1
+				return nil
+			},
+		}, Hooks{
+			BeforeRequest: func(ctx context.Context, req *ChatCompletionRequest) error {
+				order = append(order, "second")
+				return nil
+			},
+			AfterResponse: func(ctx context.Context, resp *ChatCompletionResponse) error {
+				resp.Choices[0].Message.Content = strings.ToUpper(resp.Choices[0].Message.Content)
+				return nil
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	result, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("hi"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if result != "HOLA" {
+		t.Errorf("se esperaba 'HOLA', se obtuvo '%s'", result)
+	}
+	if strings.Join(order, ",") != "first,second" {
+		t.Errorf("orden de hooks inesperado: %v", order)
+	}
+	if got := client.Requests()[0].Temperature; got != 0.1 {
+		t.Errorf("se esperaba temperatura 0.1 en el request, se obtuvo %v", got)
+	}
+}
+
+// TestHooksToolCalls verifica que los hooks de herramientas puedan reescribir argumentos y resultados.
+func TestHooksToolCalls(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "c1", Name: "echo", Args: json.RawMessage(`{"v":"original"}`)}),
+		textResponse("listo"),
+	}}
+	var received string
+	tool := &fakeTool{
+		def: ToolDefinition{Name: "echo"},
+		execFunc: func(args json.RawMessage) (interface{}, error) {
+			received = string(args)
+			return "ok", nil
+		},
+	}
+	mem := NewSimpleMemory()
+
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("hookAgent"),
+		WithMemory(mem),
+		WithModel("gpt-4o"),
+		WithTool(tool),
+		WithHooks(Hooks{
+			BeforeToolCall: func(ctx context.Context, call *ToolCall) error {
+				call.Args = json.RawMessage(`{"v":"rewritten"}`)
+				return nil
+			},
+			AfterToolCall: func(ctx context.Context, call ToolCall, result *string, err error) error {
+				*result = `"redacted"`
+				return err
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("hi")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if received != `{"v":"rewritten"}` {
+		t.Errorf("la herramienta recibió argumentos inesperados: %s", received)
+	}
+
+	found := false
+	for _, msg := range mem.Get() {
+		if msg.Role == RoleTool {
+			found = msg.Content == `"redacted"`
+		}
+	}
+	if !found {
+		t.Error("no se encontró el resultado reescrito de la herramienta en memoria")
+	}
+}
+
+// TestHooksVetoToolCall verifica que ErrSkipToolCall impida la ejecución sin abortar el chat.
+func TestHooksVetoToolCall(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "c1", Name: "danger", Args: json.RawMessage(`{}`)}),
+		textResponse("no pude hacerlo"),
+	}}
+	executed := false
+	tool := &fakeTool{
+		def: ToolDefinition{Name: "danger"},
+		execFunc: func(args json.RawMessage) (interface{}, error) {
+			executed = true
+			return nil, nil
+		},
+	}
+
+	agent, _ := NewAgent(
+		WithClient(client),
+		WithName("hookAgent"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithTool(tool),
+		WithHooks(Hooks{
+			BeforeToolCall: func(ctx context.Context, call *ToolCall) error {
+				return fmt.Errorf("policy: %w", ErrSkipToolCall)
+			},
+		}),
+	)
+
+	result, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("hi"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if executed {
+		t.Error("la herramienta no debía ejecutarse")
+	}
+	if result != "no pude hacerlo" {
+		t.Errorf("respuesta inesperada: %s", result)
+	}
+
+	msgs := client.Requests()[1].Messages
+	last := msgs[len(msgs)-1]
+	var vetoed map[string]string
+	if err := json.Unmarshal([]byte(last.Content), &vetoed); err != nil || last.Role != RoleTool ||
+		!strings.Contains(vetoed["error"], "tool call skipped") {
+		t.Errorf("se esperaba el veto como resultado de la herramienta, se obtuvo: %+v", last)
+	}
+}
+
+// TestHooksMemoryWriteAndError verifica el hook de escritura en memoria y la notificación de errores.
+func TestHooksMemoryWriteAndError(t *testing.T) {
+	mem := NewSimpleMemory()
+	var notified error
+
+	agent, _ := NewAgent(
+		WithClient(&fakeLLMClientWithError{}),
+		WithName("hookAgent"),
+		WithMemory(mem),
+		WithModel("gpt-4o"),
+		// Sin turnos atómicos, el mensaje queda en memoria aunque el chat falle.
+		WithAtomicTurns(false),
+		WithHooks(Hooks{
+			BeforeMemoryWrite: func(ctx context.Context, msg *Message) error {
+				msg.Content = strings.ReplaceAll(msg.Content, "secreto", "[REDACTED]")
+				return nil
+			},
+			OnError: func(ctx context.Context, err error) {
+				notified = err
+			},
+		}),
+	)
+
+	_, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("mi secreto"))
+	if err == nil {
+		t.Fatal("se esperaba un error del cliente LLM")
+	}
+	if notified == nil || !errors.Is(notified, err) {
+		t.Errorf("OnError no recibió el error esperado: %v", notified)
+	}
+	if got := mem.Get()[0].Content; got != "mi [REDACTED]" {
+		t.Errorf("se esperaba el mensaje redactado, se obtuvo '%s'", got)
+	}
+}
@@ -0,0 +1,466 @@
+package syndicate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// WithArgumentRepair sets whether the agent repairs malformed tool call arguments before validating
+// and executing them; it is disabled by default. The repair strips markdown fences, accepts single
+// quotes, unquoted keys, trailing commas, raw control characters in strings and Python literals,
+// closes objects and arrays truncated after a complete value, and coerces values to the type the
+// tool's schema expects where the conversion is exact (such as "3" to 3). Every repair is logged;
+// arguments that cannot be repaired safely are not passed to the tool, and the model receives an
+// InvalidToolArguments result instead.
+func WithArgumentRepair(enabled bool) AgentOption {
+	return func(a *agent) error {
+		a.repairArguments = enabled
+		return nil
+	}
+}
+
+// RepairJSON returns data as valid JSON, fixing the defects listed in WithArgumentRepair except
+// type coercion. Valid input is returned unchanged. Input that cannot be repaired without
+// guessing, such as a truncated string or a missing value, is rejected with an error.
+func RepairJSON(data []byte) (json.RawMessage, error) {
+	if json.Valid(data) {
+		return data, nil
+	}
+	value, err := parseLenientJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// repairToolArguments repairs the arguments of a tool call and coerces them to the tool's
+// parameters schema, if any. It reports whether the arguments changed.
+func repairToolArguments(schema, args json.RawMessage) (json.RawMessage, bool, error) {
+	var value any
+	if json.Valid(args) {
+		decoder := json.NewDecoder(bytes.NewReader(args))
+		decoder.UseNumber()
+		if err := decoder.Decode(&value); err != nil {
+			return nil, false, err
+		}
+	} else {
+		parsed, err := parseLenientJSON(args)
+		if err != nil {
+			return nil, false, err
+		}
+		value = parsed
+	}
+
+	coerced := false
+	var s map[string]any
+	if len(schema) > 0 && json.Unmarshal(schema, &s) == nil {
+		value, coerced = coerceToSchema(s, value)
+	}
+	if !coerced && json.Valid(args) {
+		return args, false, nil
+	}
+	repaired, err := json.Marshal(value)
+	if err != nil {
+		return nil, false, err
+	}
+	return repaired, true, nil
+}
+
+// repairCallArguments repairs the arguments of call when the agent is configured to. It returns the
+// arguments to use, or the result to send back to the model when they cannot be repaired.
+func (a *agent) repairCallArguments(ctx context.Context, tool Tool, call ToolCall) (json.RawMessage, *InvalidToolArguments) {
+	a.mutex.RLock()
+	enabled := a.repairArguments
+	a.mutex.RUnlock()
+	if !enabled || len(call.Args) == 0 {
+		return call.Args, nil
+	}
+
+	schema, _ := parametersSchema(tool.GetDefinition().Parameters)
+	args, repaired, err := repairToolArguments(schema, call.Args)
+	if err != nil {
+		a.logger.WarnContext(ctx, "tool arguments could not be repaired",
+			slog.String("agent", a.name),
+			slog.String("tool", call.Name),
+			slog.String("call_id", call.ID),
+			contentAttr("args", string(call.Args), a.debugLogging),
+			slog.Any("error", err),
+		)
+		invalid := newInvalidToolArguments(call.Name, []string{"$: invalid JSON: " + err.Error()})
+		return nil, &invalid
+	}
+	if repaired {
+		a.logger.InfoContext(ctx, "tool arguments repaired",
+			slog.String("agent", a.name),
+			slog.String("tool", call.Name),
+			slog.String("call_id", call.ID),
+			contentAttr("args", string(call.Args), a.debugLogging),
+			contentAttr("repaired_args", string(args), a.debugLogging),
+		)
+	}
+	return args, nil
+}
+
+// lenientParser parses JSON with the defects accepted by RepairJSON.
+type lenientParser struct {
+	data []byte
+	pos  int
+}
+
+// parseLenientJSON parses data into the values produced by json.Decoder.UseNumber.
+func parseLenientJSON(data []byte) (any, error) {
+	text := strings.TrimSpace(string(data))
+	if strings.HasPrefix(text, "```") {
+		text = trimCodeFence(text)
+		text = strings.TrimSpace(strings.TrimPrefix(text, "json"))
+	}
+	if text == "" {
+		return nil, errors.New("empty input")
+	}
+
+	p := &lenientParser{data: []byte(text)}
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.data) {
+		return nil, fmt.Errorf("unexpected %q after the value at offset %d", p.data[p.pos], p.pos)
+	}
+	return value, nil
+}
+
+// errTruncated reports input that ends where a value is still needed.
+var errTruncated = errors.New("unexpected end of input")
+
+func (p *lenientParser) skipSpace() {
+	for p.pos < len(p.data) {
+		switch p.data[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *lenientParser) value() (any, error) {
+	p.skipSpace()
+	if p.pos >= len(p.data) {
+		return nil, errTruncated
+	}
+	switch c := p.data[p.pos]; {
+	case c == '{':
+		return p.object()
+	case c == '[':
+		return p.array()
+	case c == '"' || c == '\'':
+		return p.string()
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		return p.number()
+	case isIdentifierStart(c):
+		return p.literal()
+	default:
+		return nil, fmt.Errorf("unexpected %q at offset %d", c, p.pos)
+	}
+}
+
+// object parses an object, tolerating unquoted keys, trailing commas and a missing closing brace
+// after a complete member.
+func (p *lenientParser) object() (any, error) {
+	p.pos++ // {
+	object := make(map[string]any)
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return object, nil
+		}
+		switch p.data[p.pos] {
+		case '}':
+			p.pos++
+			return object, nil
+		case ',':
+			p.pos++
+			continue
+		}
+
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return nil, errTruncated
+		}
+		if p.data[p.pos] != ':' {
+			return nil, fmt.Errorf("expected ':' after key %q at offset %d", key, p.pos)
+		}
+		p.pos++
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		object[key] = value
+
+		p.skipSpace()
+		if p.pos < len(p.data) && p.data[p.pos] != ',' && p.data[p.pos] != '}' {
+			return nil, fmt.Errorf("expected ',' or '}' at offset %d", p.pos)
+		}
+	}
+}
+
+// key parses an object key, quoted or not.
+func (p *lenientParser) key() (string, error) {
+	c := p.data[p.pos]
+	if c == '"' || c == '\'' {
+		return p.string()
+	}
+	if !isIdentifierStart(c) {
+		return "", fmt.Errorf("unexpected %q at offset %d", c, p.pos)
+	}
+	start := p.pos
+	for p.pos < len(p.data) && isIdentifierPart(p.data[p.pos]) {
+		p.pos++
+	}
+	return string(p.data[start:p.pos]), nil
+}
+
+// array parses an array, tolerating trailing commas and a missing closing bracket after a
+// complete element.
+func (p *lenientParser) array() (any, error) {
+	p.pos++ // [
+	array := []any{}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.data) {
+			return array, nil
+		}
+		switch p.data[p.pos] {
+		case ']':
+			p.pos++
+			return array, nil
+		case ',':
+			p.pos++
+			continue
+		}
+
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		array = append(array, value)
+
+		p.skipSpace()
+		if p.pos < len(p.data) && p.data[p.pos] != ',' && p.data[p.pos] != ']' {
+			return nil, fmt.Errorf("expected ',' or ']' at offset %d", p.pos)
+		}
+	}
+}
+
+// string parses a string in double or single quotes, escaping raw control characters and
+// keeping unknown escapes literally. A string cut off by the end of the input is rejected,
+// since its value is unknown.
+func (p *lenientParser) string() (string, error) {
+	quote := p.data[p.pos]
+	start := p.pos
+	p.pos++
+
+	var literal bytes.Buffer
+	literal.WriteByte('"')
+	for {
+		if p.pos >= len(p.data) {
+			return "", fmt.Errorf("unterminated string at offset %d", start)
+		}
+		c := p.data[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			literal.WriteByte('"')
+			var s string
+			if err := json.Unmarshal(literal.Bytes(), &s); err != nil {
+				return "", fmt.Errorf("invalid string at offset %d: %w", start, err)
+			}
+			return s, nil
+		case c == '\\':
+			if p.pos >= len(p.data) {
+				return "", fmt.Errorf("unterminated string at offset %d", start)
+			}
+			next := p.data[p.pos]
+			p.pos++
+			switch next {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't', 'u':
+				literal.WriteByte('\\')
+				literal.WriteByte(next)
+			case '\'':
+				literal.WriteByte('\'')
+			default:
+				literal.WriteString(`\\`)
+				literal.WriteByte(next)
+			}
+		case c == '"':
+			literal.WriteString(`\"`)
+		case c < 0x20:
+			encoded, _ := json.Marshal(string(c))
+			literal.Write(encoded[1 : len(encoded)-1])
+		default:
+			literal.WriteByte(c)
+		}
+	}
+}
+
+// number parses a number, accepting a leading plus sign or decimal point. A number cut off by the
+// end of the input is rejected, since more digits may be missing.
+func (p *lenientParser) number() (any, error) {
+	start := p.pos
+	for p.pos < len(p.data) && strings.IndexByte("+-.eE0123456789", p.data[p.pos]) >= 0 {
+		p.pos++
+	}
+	if p.pos == len(p.data) && start > 0 {
+		return nil, fmt.Errorf("truncated number at offset %d", start)
+	}
+	text := strings.TrimPrefix(string(p.data[start:p.pos]), "+")
+	if strings.HasPrefix(text, ".") {
+		text = "0" + text
+	} else if strings.HasPrefix(text, "-.") {
+		text = "-0" + text[1:]
+	}
+	if !json.Valid([]byte(text)) {
+		return nil, fmt.Errorf("invalid number %q at offset %d", p.data[start:p.pos], start)
+	}
+	return json.Number(text), nil
+}
+
+// literal parses true, false and null, also in their Python spelling.
+func (p *lenientParser) literal() (any, error) {
+	start := p.pos
+	for p.pos < len(p.data) && isIdentifierPart(p.data[p.pos]) {
+		p.pos++
+	}
+	switch word := string(p.data[start:p.pos]); word {
+	case "true", "True":
+		return true, nil
+	case "false", "False":
+		return false, nil
+	case "null", "None":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at offset %d", word, start)
+	}
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentifierPart(c byte) bool {
+	return isIdentifierStart(c) || (c >= '0' && c <= '9')
+}
+
+// coerceToSchema converts value, recursively, to the types allowed by schema where the conversion
+// is exact, and reports whether anything changed. Values are those decoded with UseNumber.
+func coerceToSchema(schema map[string]any, value any) (any, bool) {
+	types := schemaTypes(schema["type"])
+	changed := false
+
+	switch v := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		for key, item := range v {
+			if propertySchema, ok := properties[key].(map[string]any); ok {
+				if coerced, ok := coerceToSchema(propertySchema, item); ok {
+					v[key] = coerced
+					changed = true
+				}
+			}
+		}
+		return v, changed
+	case []any:
+		items, _ := schema["items"].(map[string]any)
+		if items == nil {
+			return v, false
+		}
+		for i, item := range v {
+			if coerced, ok := coerceToSchema(items, item); ok {
+				v[i] = coerced
+				changed = true
+			}
+		}
+		return v, changed
+	}
+
+	if len(types) == 0 || typeAllowed(types, value) {
+		return value, false
+	}
+	for _, t := range types {
+		if coerced, ok := coerceScalar(DataType(t), value); ok {
+			return coerced, true
+		}
+	}
+	return value, false
+}
+
+// typeAllowed reports whether a scalar decoded with UseNumber has one of the given types.
+func typeAllowed(types []string, value any) bool {
+	for _, t := range types {
+		switch v := value.(type) {
+		case json.Number:
+			if DataType(t) == Number {
+				return true
+			}
+			if _, err := v.Int64(); err == nil && DataType(t) == Integer {
+				return true
+			}
+		default:
+			if jsonTypeOf(value) == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// coerceScalar converts a scalar to type t when the conversion loses nothing.
+func coerceScalar(t DataType, value any) (any, bool) {
+	switch v := value.(type) {
+	case string:
+		s := strings.TrimSpace(v)
+		switch t {
+		case Integer:
+			// Formatting the parsed value drops signs and leading zeros that JSON does not allow.
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return json.Number(strconv.FormatInt(n, 10)), true
+			}
+		case Number:
+			if _, err := strconv.ParseFloat(s, 64); err == nil && json.Valid([]byte(s)) {
+				return json.Number(s), true
+			}
+		case Boolean:
+			switch strings.ToLower(s) {
+			case "true":
+				return true, true
+			case "false":
+				return false, true
+			}
+		}
+	case json.Number:
+		switch t {
+		case String:
+			return v.String(), true
+		case Integer:
+			if f, err := v.Float64(); err == nil && f == float64(int64(f)) {
+				return json.Number(strconv.FormatInt(int64(f), 10)), true
+			}
+		}
+	case bool:
+		if t == String {
+			return strconv.FormatBool(v), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,119 @@
+package syndicate
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestRepairJSON verifica la reparación de los defectos comunes y el rechazo de lo irreparable.
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"válido sin cambios", `{"b": 1, "a": [1,2]}`, `{"b": 1, "a": [1,2]}`},
+		{"coma final", `{"a": 1, "b": [1, 2,],}`, `{"a":1,"b":[1,2]}`},
+		{"comillas simples", `{'a': 'it\'s "x"'}`, `{"a":"it's \"x\""}`},
+		{"salto de línea sin escapar", "{\"a\": \"uno\ndos\"}", `{"a":"uno\ndos"}`},
+		{"bloque markdown", "```json\n{\"a\": 1}\n```", `{"a":1}`},
+		{"bloque en una línea", "```json {\"a\": 1}```", `{"a":1}`},
+		{"claves sin comillas y literales de Python", `{ciudad: "Lima", activo: True, extra: None}`, `{"activo":true,"ciudad":"Lima","extra":null}`},
+		{"objeto truncado", `{"a": {"b": [1, 2`, ``},
+		{"objeto truncado tras un valor completo", `{"a": {"b": "x"`, `{"a":{"b":"x"}}`},
+		{"escape desconocido", `{"ruta": "C:\dir"}`, `{"ruta":"C:\\dir"}`},
+		{"número con signo más", `{"n": +.5}`, `{"n":0.5}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RepairJSON([]byte(tt.input))
+			if tt.want == "" {
+				if err == nil {
+					t.Errorf("se esperaba error, se obtuvo %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("se esperaba %s, se obtuvo %s", tt.want, got)
+			}
+		})
+	}
+
+	for _, input := range []string{``, `{"a": "sin cerrar`, `{"a": }`, `{"a": 1 "b": 2}`, `{"a":`, `{"a" 1}`, `{"a": 1} extra`, `{"a": nada}`} {
+		if got, err := RepairJSON([]byte(input)); err == nil {
+			t.Errorf("se esperaba error para %q, se obtuvo %s", input, got)
+		}
+	}
+}
+
+// TestRepairToolArguments verifica la conversión de tipos según el esquema.
+func TestRepairToolArguments(t *testing.T) {
+	schema, err := GenerateRawSchema(struct {
+		Quantity int      `json:"quantity" description:"Cantidad"`
+		Price    float64  `json:"price" description:"Precio"`
+		Gift     bool     `json:"gift" description:"Regalo"`
+		Code     string   `json:"code" description:"Código"`
+		Sizes    []int    `json:"sizes" description:"Tallas"`
+		Notes    []string `json:"notes" description:"Notas"`
+	}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args, repaired, err := repairToolArguments(schema, json.RawMessage(`{"quantity":"3","price":" 9.5","gift":"TRUE","code":1234,"sizes":["1",2.0],"notes":[true]}`))
+	if err != nil || !repaired {
+		t.Fatalf("se esperaba reparación: %v %v", repaired, err)
+	}
+	if string(args) != `{"code":"1234","gift":true,"notes":["true"],"price":9.5,"quantity":3,"sizes":[1,2]}` {
+		t.Errorf("argumentos inesperados: %s", args)
+	}
+
+	valid := json.RawMessage(`{"quantity": 3, "price": 1.25, "gift": false, "code": "x", "sizes": [], "notes": []}`)
+	if args, repaired, err := repairToolArguments(schema, valid); err != nil || repaired || string(args) != string(valid) {
+		t.Errorf("los argumentos válidos no debían cambiar: %s %v %v", args, repaired, err)
+	}
+
+	for input, want := range map[string]string{"+3": "3", "007": "7", " 3": "3", "-0": "0"} {
+		raw, _ := json.Marshal(map[string]any{"quantity": input})
+		args, repaired, err := repairToolArguments(schema, raw)
+		if err != nil || !repaired || string(args) != `{"quantity":`+want+`}` {
+			t.Errorf("entero %q reparado como %s (%v %v)", input, args, repaired, err)
+		}
+	}
+
+	args, _, err = repairToolArguments(schema, json.RawMessage(`{"quantity":"tres","price":"1e400x"}`))
+	if err != nil || !strings.Contains(string(args), `"quantity":"tres"`) {
+		t.Errorf("las conversiones inexactas debían dejarse para la validación: %s %v", args, err)
+	}
+}
+
+// TestAgentArgumentRepair verifica que el agente repare los argumentos, lo registre y rechace lo irreparable.
+func TestAgentArgumentRepair(t *testing.T) {
+	logger, buf := newBufferLogger()
+	var executed []string
+	runOrderChat(t, newOrderTool(t, &executed), "```json\n{product: 'polera', quantity: \"2\", size: 'M',}\n```",
+		WithArgumentRepair(true), WithLogger(logger))
+	if len(executed) != 1 || executed[0] != `{"product":"polera","quantity":2,"size":"M"}` {
+		t.Errorf("la herramienta debía recibir los argumentos reparados: %v", executed)
+	}
+	if !strings.Contains(buf.String(), "tool arguments repaired") {
+		t.Errorf("la reparación debía registrarse: %s", buf.String())
+	}
+
+	executed = nil
+	requests := runOrderChat(t, newOrderTool(t, &executed), `{"product": "pol`, WithArgumentRepair(true))
+	feedback := requests[1].Messages[len(requests[1].Messages)-1]
+	if len(executed) != 0 || !strings.Contains(feedback.Content, "unterminated string") {
+		t.Errorf("los argumentos irreparables no debían ejecutarse: %v %q", executed, feedback.Content)
+	}
+
+	executed = nil
+	runOrderChat(t, newOrderTool(t, &executed), `{"product":"polera","quantity":"2","size":"M"}`)
+	if len(executed) != 0 {
+		t.Errorf("sin reparación la validación debía rechazar los argumentos: %v", executed)
+	}
+}
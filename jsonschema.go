@@ -3,7 +3,10 @@ package syndicate
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -268,3 +271,204 @@ func reflectSchemaObject(t reflect.Type) (*Definition, error) {
 	def.Required = requiredFields
 	return &def, nil
 }
+
+// SchemaValidationError lists the ways a JSON document does not conform to a schema.
+type SchemaValidationError struct {
+	Problems []string // Each problem is prefixed with the path of the offending value, such as "$.items[0].name".
+}
+
+func (e *SchemaValidationError) Error() string {
+	return "document does not match schema: " + strings.Join(e.Problems, "; ")
+}
+
+// ValidateJSON checks that document conforms to schema. It supports the keywords produced by
+// GenerateRawSchema (type, properties, required, items, enum and additionalProperties) plus
+// minimum, maximum, minLength, maxLength, pattern, minItems and maxItems.
+// A document that does not conform is reported as a *SchemaValidationError.
+func ValidateJSON(schema, document json.RawMessage) error {
+	var s map[string]any
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	var value any
+	if err := json.Unmarshal(document, &value); err != nil {
+		return &SchemaValidationError{Problems: []string{fmt.Sprintf("$: invalid JSON: %v", err)}}
+	}
+
+	var problems []string
+	validateValue("$", s, value, &problems)
+	if len(problems) > 0 {
+		return &SchemaValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateValue checks value against schema, appending any problem found to problems.
+func validateValue(path string, schema map[string]any, value any, problems *[]string) {
+	report := func(format string, args ...any) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if jsonTypeMatches(t, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			report("expected %s, got %s", strings.Join(types, " or "), jsonTypeOf(value))
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, allowed := range enum {
+			if enumValueMatches(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report("value %v is not one of the allowed values", value)
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := len([]rune(v))
+		if min, ok := schema["minLength"].(float64); ok && float64(length) < min {
+			report("length %d is less than %v", length, min)
+		}
+		if max, ok := schema["maxLength"].(float64); ok && float64(length) > max {
+			report("length %d is greater than %v", length, max)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				report("does not match pattern %q", pattern)
+			}
+		}
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && v < min {
+			report("%v is less than %v", v, min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && v > max {
+			report("%v is greater than %v", v, max)
+		}
+	case []any:
+		if min, ok := schema["minItems"].(float64); ok && float64(len(v)) < min {
+			report("has %d items, fewer than %v", len(v), min)
+		}
+		if max, ok := schema["maxItems"].(float64); ok && float64(len(v)) > max {
+			report("has %d items, more than %v", len(v), max)
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				validateValue(fmt.Sprintf("%s[%d]", path, i), items, item, problems)
+			}
+		}
+	case map[string]any:
+		if required, ok := schema["required"].([]any); ok {
+			for _, name := range required {
+				if key, ok := name.(string); ok {
+					if _, present := v[key]; !present {
+						report("missing required property %q", key)
+					}
+				}
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		for _, key := range sortedMapKeys(v) {
+			childPath := path + "." + key
+			if prop, ok := properties[key].(map[string]any); ok {
+				validateValue(childPath, prop, v[key], problems)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					report("unexpected property %q", key)
+				}
+			case map[string]any:
+				validateValue(childPath, additional, v[key], problems)
+			}
+		}
+	}
+}
+
+// enumValueMatches reports whether value equals an allowed enum value. GenerateRawSchema writes
+// enum values as strings, so numbers are also compared with the number each string encodes.
+func enumValueMatches(allowed, value any) bool {
+	if reflect.DeepEqual(allowed, value) {
+		return true
+	}
+	s, isString := allowed.(string)
+	n, isNumber := value.(float64)
+	if !isString || !isNumber {
+		return false
+	}
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return err == nil && parsed == n
+}
+
+// schemaTypes returns the types allowed by a "type" keyword, which may be a string or a list.
+func schemaTypes(keyword any) []string {
+	switch t := keyword.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// jsonTypeMatches reports whether a decoded JSON value has the given schema type.
+func jsonTypeMatches(t string, value any) bool {
+	switch DataType(t) {
+	case Integer:
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case Number:
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonTypeOf(value) == t
+	}
+}
+
+// jsonTypeOf returns the schema type name of a decoded JSON value.
+func jsonTypeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return string(Null)
+	case bool:
+		return string(Boolean)
+	case float64:
+		return string(Number)
+	case string:
+		return string(String)
+	case []any:
+		return string(Array)
+	case map[string]any:
+		return string(Object)
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// sortedMapKeys returns the keys of m in alphabetical order, so problems are reported deterministically.
+func sortedMapKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
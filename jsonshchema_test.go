@@ -2,6 +2,7 @@ package syndicate
 
 import (
 	"encoding/json"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
@@ -180,3 +181,107 @@ func TestReflectSchemaUnsupportedType(t *testing.T) {
 		t.Errorf("Se esperaba error para tipo no soportado (chan), se obtuvo: %v", err)
 	}
 }
+
+// --- Tests de ValidateJSON ---
+
+func TestValidateJSONGeneratedSchema(t *testing.T) {
+	type item struct {
+		Name  string  `json:"name"`
+		Price float64 `json:"price"`
+	}
+	type order struct {
+		ID     int    `json:"id"`
+		Status string `json:"status" enum:"open,closed"`
+		Items  []item `json:"items"`
+	}
+	schema, err := GenerateRawSchema(order{})
+	if err != nil {
+		t.Fatalf("GenerateRawSchema falló: %v", err)
+	}
+
+	valid := `{"id": 1, "status": "open", "items": [{"name": "a", "price": 2.5}]}`
+	if err := ValidateJSON(schema, json.RawMessage(valid)); err != nil {
+		t.Errorf("no se esperaba error: %v", err)
+	}
+
+	invalid := `{"id": 1.5, "status": "lost", "items": [{"name": 3}], "extra": true}`
+	err = ValidateJSON(schema, json.RawMessage(invalid))
+	var schemaErr *SchemaValidationError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("se esperaba SchemaValidationError, se obtuvo: %v", err)
+	}
+	expected := []string{
+		"$.id: expected integer, got number",
+		"$.status: value lost is not one of the allowed values",
+		"$.items[0]: missing required property \"price\"",
+		"$.items[0].name: expected string, got number",
+		"$: unexpected property \"extra\"",
+	}
+	for _, want := range expected {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("falta el problema %q en: %v", want, err)
+		}
+	}
+}
+
+func TestValidateJSONConstraints(t *testing.T) {
+	schema := json.RawMessage(`{"type": "object", "properties": {
+		"code": {"type": "string", "pattern": "^[A-Z]{3}$", "maxLength": 3},
+		"qty": {"type": "integer", "minimum": 1, "maximum": 10},
+		"tags": {"type": "array", "items": {"type": "string"}, "minItems": 1},
+		"note": {"type": ["string", "null"]}
+	}}`)
+
+	if err := ValidateJSON(schema, json.RawMessage(`{"code": "ABC", "qty": 3, "tags": ["x"], "note": null}`)); err != nil {
+		t.Errorf("no se esperaba error: %v", err)
+	}
+
+	err := ValidateJSON(schema, json.RawMessage(`{"code": "abcd", "qty": 11, "tags": []}`))
+	if err == nil {
+		t.Fatal("se esperaba un error de validación")
+	}
+	for _, want := range []string{"$.code: length 4", "$.code: does not match pattern", "$.qty: 11 is greater than 10", "$.tags: has 0 items"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("falta el problema %q en: %v", want, err)
+		}
+	}
+
+	if err := ValidateJSON(schema, json.RawMessage(`{not json`)); err == nil || !strings.Contains(err.Error(), "invalid JSON") {
+		t.Errorf("se esperaba un error de JSON inválido, se obtuvo: %v", err)
+	}
+	if err := ValidateJSON(json.RawMessage(`[`), json.RawMessage(`{}`)); err == nil || !strings.Contains(err.Error(), "invalid schema") {
+		t.Errorf("se esperaba un error de esquema inválido, se obtuvo: %v", err)
+	}
+}
+
+// TestValidateJSONNumericEnum verifica que los enums numéricos, generados como texto, acepten números.
+func TestValidateJSONNumericEnum(t *testing.T) {
+	type input struct {
+		Level int     `json:"level" enum:"1,2"`
+		Ratio float64 `json:"ratio" enum:"0.5, 1.5"`
+	}
+	schema, err := GenerateRawSchema(input{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ValidateJSON(schema, json.RawMessage(`{"level": 1, "ratio": 1.5}`)); err != nil {
+		t.Errorf("no se esperaba error: %v", err)
+	}
+	if err := ValidateJSON(schema, json.RawMessage(`{"level": 2.0, "ratio": 0.5}`)); err != nil {
+		t.Errorf("no se esperaba error: %v", err)
+	}
+
+	err = ValidateJSON(schema, json.RawMessage(`{"level": 3, "ratio": 1}`))
+	if err == nil {
+		t.Fatal("se esperaba un error de validación")
+	}
+	for _, want := range []string{"$.level: value 3 is not one of the allowed values", "$.ratio: value 1 is not one of the allowed values"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("falta el problema %q en: %v", want, err)
+		}
+	}
+	if err := ValidateJSON(schema, json.RawMessage(`{"level": "1", "ratio": 0.5}`)); err == nil {
+		t.Error("se esperaba un error por tipo incorrecto")
+	}
+}
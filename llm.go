@@ -3,6 +3,7 @@ package syndicate
 import (
 	"context"
 	"encoding/json"
+	"log/slog"
 )
 
 // Role constants define standard message roles across different providers
@@ -26,6 +27,24 @@ type LLMClient interface {
 	CreateChatCompletion(ctx context.Context, req ChatCompletionRequest) (ChatCompletionResponse, error)
 }
 
+// ClientOption configures optional behaviour of the built-in LLM clients.
+type ClientOption func(*clientConfig)
+
+// clientConfig holds the optional settings shared by the built-in LLM clients.
+type clientConfig struct {
+	logger *slog.Logger
+}
+
+// newClientConfig applies the given options over the defaults.
+func newClientConfig(options []ClientOption) clientConfig {
+	config := clientConfig{}
+	for _, option := range options {
+		option(&config)
+	}
+	config.logger = loggerOrDiscard(config.logger)
+	return config
+}
+
 // ChatCompletionRequest represents a unified chat completion request.
 type ChatCompletionRequest struct {
 	Model          string           `json:"model"`
@@ -36,13 +55,18 @@ type ChatCompletionRequest struct {
 }
 
 // Message represents a chat message with standardized fields.
+//
+// CacheBreakpoint marks the end of a prompt prefix worth caching, for providers that support
+// explicit cache breakpoints. It is a hint: the built-in OpenAI and DeepSeek clients ignore it,
+// since those providers cache prompt prefixes automatically.
 type Message struct {
-	Role       string     `json:"role"`
-	Content    string     `json:"content"`
-	Name       string     `json:"name,omitempty"`
-	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
-	ToolCallID string     `json:"tool_call_id,omitempty"`
-	ImageURLs  []string   `json:"image_urls,omitempty"`
+	Role            string     `json:"role"`
+	Content         string     `json:"content"`
+	Name            string     `json:"name,omitempty"`
+	ToolCalls       []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID      string     `json:"tool_call_id,omitempty"`
+	ImageURLs       []string   `json:"image_urls,omitempty"`
+	CacheBreakpoint bool       `json:"cache_breakpoint,omitempty"`
 }
 
 // ToolCall represents a tool invocation request.
@@ -91,8 +115,10 @@ type Choice struct {
 }
 
 // Usage provides token usage statistics.
+// CachedPromptTokens is the part of PromptTokens served from the provider's prompt cache.
 type Usage struct {
-	PromptTokens     int `json:"prompt_tokens"`
-	CompletionTokens int `json:"completion_tokens"`
-	TotalTokens      int `json:"total_tokens"`
+	PromptTokens       int `json:"prompt_tokens"`
+	CompletionTokens   int `json:"completion_tokens"`
+	TotalTokens        int `json:"total_tokens"`
+	CachedPromptTokens int `json:"cached_prompt_tokens,omitempty"`
 }
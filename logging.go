@@ -0,0 +1,86 @@
+package syndicate
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// Log levels used across the package:
+//   - Debug: per-request details (LLM rounds, tool executions, pipeline stages, latency and tokens).
+//   - Info:  completed chat turns and pipeline runs.
+//   - Warn:  recoverable problems, such as a tool call vetoed by a hook.
+//   - Error: failed provider calls, tool executions and chat turns.
+//
+// Message content (prompts, answers, tool arguments and results) is never logged unless
+// debug logging is explicitly enabled with WithDebugLogging; only content lengths are recorded.
+// Every component logs to a discarding logger until one is injected.
+
+// discardLogger returns a logger that drops every record.
+func discardLogger() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// loggerOrDiscard returns logger, or a discarding logger when it is nil.
+func loggerOrDiscard(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return discardLogger()
+	}
+	return logger
+}
+
+// contentAttr returns a log attribute for message content, redacted to its length unless debug is set.
+func contentAttr(key, content string, debug bool) slog.Attr {
+	if debug {
+		return slog.String(key, content)
+	}
+	return slog.Int(key+"_length", len(content))
+}
+
+// usageLogAttrs converts token usage into log attributes.
+func usageLogAttrs(usage Usage) []any {
+	return []any{
+		slog.Int("prompt_tokens", usage.PromptTokens),
+		slog.Int("completion_tokens", usage.CompletionTokens),
+		slog.Int("total_tokens", usage.TotalTokens),
+		slog.Int("cached_prompt_tokens", usage.CachedPromptTokens),
+	}
+}
+
+// WithLogger sets the structured logger used by the agent.
+func WithLogger(logger *slog.Logger) AgentOption {
+	return func(a *agent) error {
+		if logger == nil {
+			return errors.New("logger cannot be nil")
+		}
+		a.logger = logger
+		return nil
+	}
+}
+
+// WithDebugLogging makes the agent include prompts, answers and tool payloads in its Debug logs.
+// Only enable it where logging conversation content is acceptable.
+func WithDebugLogging() AgentOption {
+	return func(a *agent) error {
+		a.debugLogging = true
+		return nil
+	}
+}
+
+// WithSyndicateLogger sets the structured logger used by the syndicate.
+func WithSyndicateLogger(logger *slog.Logger) SyndicateOption {
+	return func(s *syndicate) error {
+		if logger == nil {
+			return fmt.Errorf("logger cannot be nil")
+		}
+		s.logger = logger
+		return nil
+	}
+}
+
+// WithClientLogger sets the structured logger used by an LLM client.
+func WithClientLogger(logger *slog.Logger) ClientOption {
+	return func(c *clientConfig) {
+		c.logger = logger
+	}
+}
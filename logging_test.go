@@ -0,0 +1,150 @@
+package syndicate
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// newBufferLogger crea un logger JSON que escribe en un buffer a partir del nivel Debug.
+func newBufferLogger() (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})), &buf
+}
+
+// TestAgentLoggingRedactsContent verifica que por defecto no se registre el contenido de los mensajes.
+func TestAgentLoggingRedactsContent(t *testing.T) {
+	logger, buf := newBufferLogger()
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("respuesta confidencial")}}
+
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("logged"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithLogger(logger),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("dato sensible")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	output := buf.String()
+	if strings.Contains(output, "dato sensible") || strings.Contains(output, "respuesta confidencial") {
+		t.Errorf("el log no debería contener el contenido de los mensajes: %s", output)
+	}
+	for _, want := range []string{`"agent":"logged"`, `"model":"gpt-4o"`, `"input_length":13`, `"msg":"chat completed"`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("se esperaba %s en el log: %s", want, output)
+		}
+	}
+}
+
+// TestAgentDebugLoggingIncludesPrompts verifica que el modo debug incluya prompts y respuestas.
+func TestAgentDebugLoggingIncludesPrompts(t *testing.T) {
+	logger, buf := newBufferLogger()
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("respuesta visible")}}
+
+	agent, _ := NewAgent(
+		WithClient(client),
+		WithName("logged"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithSystemPrompt("prompt de sistema"),
+		WithLogger(logger),
+		WithDebugLogging(),
+	)
+
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("pregunta visible")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"pregunta visible", "respuesta visible", "prompt de sistema"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("se esperaba %q en el log de debug: %s", want, output)
+		}
+	}
+}
+
+// TestAgentLoggingErrors verifica que los errores del proveedor se registren con nivel Error.
+func TestAgentLoggingErrors(t *testing.T) {
+	logger, buf := newBufferLogger()
+	agent, _ := NewAgent(
+		WithClient(&fakeLLMClientWithError{}),
+		WithName("logged"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithLogger(logger),
+	)
+
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("hola")); err == nil {
+		t.Fatal("se esperaba un error")
+	}
+	if !strings.Contains(buf.String(), `"level":"ERROR","msg":"chat completion failed"`) {
+		t.Errorf("se esperaba un registro de error: %s", buf.String())
+	}
+}
+
+// TestLoggerOptionsValidation verifica que no se acepten loggers nil.
+func TestLoggerOptionsValidation(t *testing.T) {
+	if _, err := NewAgent(WithLogger(nil)); err == nil || !strings.Contains(err.Error(), "logger cannot be nil") {
+		t.Errorf("se esperaba error por logger nil, se obtuvo: %v", err)
+	}
+	if _, err := NewSyndicate(WithSyndicateLogger(nil)); err == nil || !strings.Contains(err.Error(), "logger cannot be nil") {
+		t.Errorf("se esperaba error por logger nil, se obtuvo: %v", err)
+	}
+}
+
+// TestEmbedderLogsErrors verifica que el Embedder use el logger inyectado en lugar del log global.
+func TestEmbedderLogsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "simulated error", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	config := openai.DefaultConfig("dummy-key")
+	config.BaseURL = server.URL
+	logger, buf := newBufferLogger()
+
+	embedder, err := NewEmbedderBuilder().
+		SetClient(openai.NewClientWithConfig(config)).
+		SetLogger(logger).
+		Build()
+	if err != nil {
+		t.Fatalf("error construyendo embedder: %v", err)
+	}
+
+	if _, err := embedder.GenerateEmbedding(context.Background(), "texto"); err == nil {
+		t.Fatal("se esperaba un error")
+	}
+	if !strings.Contains(buf.String(), "failed to create embeddings") {
+		t.Errorf("se esperaba el error en el logger inyectado: %s", buf.String())
+	}
+}
+
+// TestOpenAIClientLogger verifica que el cliente de OpenAI registre las llamadas fallidas.
+func TestOpenAIClientLogger(t *testing.T) {
+	logger, buf := newBufferLogger()
+	client := NewOpenAIClient("dummy-key", WithClientLogger(logger)).(*OpenAIClient)
+
+	config := openai.DefaultConfig("dummy-key")
+	config.BaseURL = "http://127.0.0.1:0"
+	client.client = openai.NewClientWithConfig(config)
+
+	if _, err := client.CreateChatCompletion(context.Background(), ChatCompletionRequest{Model: "gpt-4o"}); err == nil {
+		t.Fatal("se esperaba un error")
+	}
+	if !strings.Contains(buf.String(), "openai chat completion failed") {
+		t.Errorf("se esperaba el error en el logger del cliente: %s", buf.String())
+	}
+}
@@ -3,7 +3,12 @@ package syndicate
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
 
 	openai "github.com/sashabaranov/go-openai"
 )
@@ -12,24 +17,27 @@ import (
 // It wraps the official OpenAI client and provides a consistent interface for making chat completion requests.
 type OpenAIClient struct {
 	client *openai.Client
+	logger *slog.Logger
 }
 
 // NewOpenAIAzureClient creates an LLMClient for Azure using Azure provider-specific settings.
 // It configures the client with Azure-specific settings.
-func NewOpenAIAzureClient(apiKey string) LLMClient {
+func NewOpenAIAzureClient(apiKey string, options ...ClientOption) LLMClient {
 	config := openai.DefaultConfig(apiKey)
 	config.BaseURL = "https://models.inference.ai.azure.com"
 	client := openai.NewClientWithConfig(config)
 
 	return &OpenAIClient{
 		client: client,
+		logger: newClientConfig(options).logger,
 	}
 }
 
 // NewOpenAIClient creates a new LLMClient using the provided API key with the standard OpenAI endpoint.
-func NewOpenAIClient(apiKey string) LLMClient {
+func NewOpenAIClient(apiKey string, options ...ClientOption) LLMClient {
 	return &OpenAIClient{
 		client: openai.NewClient(apiKey),
+		logger: newClientConfig(options).logger,
 	}
 }
 
@@ -154,21 +162,27 @@ func mapFromOpenAIResponse(resp openai.ChatCompletionResponse) ChatCompletionRes
 			FinishReason: string(c.FinishReason),
 		})
 	}
-	usage := Usage{
-		PromptTokens:     resp.Usage.PromptTokens,
-		CompletionTokens: resp.Usage.CompletionTokens,
-		TotalTokens:      resp.Usage.TotalTokens,
-	}
 	return ChatCompletionResponse{
 		Choices: choices,
-		Usage:   usage,
+		Usage:   mapFromOpenAIUsage(resp.Usage),
 	}
 }
 
-// CreateChatCompletion sends a chat completion request to the OpenAI API using the provided request parameters.
-// It converts internal messages and tool definitions to OpenAI formats, sends the request,
-// and maps the response back into the SDK's unified structure.
-func (o *OpenAIClient) CreateChatCompletion(ctx context.Context, req ChatCompletionRequest) (ChatCompletionResponse, error) {
+// mapFromOpenAIUsage converts OpenAI token usage, including cached prompt tokens, into the internal format.
+func mapFromOpenAIUsage(usage openai.Usage) Usage {
+	result := Usage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+	if usage.PromptTokensDetails != nil {
+		result.CachedPromptTokens = usage.PromptTokensDetails.CachedTokens
+	}
+	return result
+}
+
+// mapToOpenAIRequest converts an internal ChatCompletionRequest into an OpenAI request.
+func mapToOpenAIRequest(req ChatCompletionRequest) openai.ChatCompletionRequest {
 	openaiReq := openai.ChatCompletionRequest{
 		Model:       req.Model,
 		Messages:    mapToOpenAIMessages(req.Messages),
@@ -186,13 +200,151 @@ func (o *OpenAIClient) CreateChatCompletion(ctx context.Context, req ChatComplet
 			},
 		}
 	}
+	return openaiReq
+}
+
+// CreateChatCompletion sends a chat completion request to the OpenAI API using the provided request parameters.
+// It converts internal messages and tool definitions to OpenAI formats, sends the request,
+// and maps the response back into the SDK's unified structure.
+func (o *OpenAIClient) CreateChatCompletion(ctx context.Context, req ChatCompletionRequest) (ChatCompletionResponse, error) {
+	openaiReq := mapToOpenAIRequest(req)
 
 	// Send the request to the OpenAI API.
+	logger := loggerOrDiscard(o.logger)
+	start := time.Now()
 	resp, err := o.client.CreateChatCompletion(ctx, openaiReq)
 	if err != nil {
+		logger.ErrorContext(ctx, "openai chat completion failed",
+			slog.String("model", req.Model),
+			slog.Duration("latency", time.Since(start)),
+			slog.Any("error", err),
+		)
 		return ChatCompletionResponse{}, fmt.Errorf("openai error: %w", err)
 	}
 
 	// Map the OpenAI response into our internal unified format.
-	return mapFromOpenAIResponse(resp), nil
+	result := mapFromOpenAIResponse(resp)
+	logger.DebugContext(ctx, "openai chat completion",
+		append([]any{
+			slog.String("model", req.Model),
+			slog.Int("messages", len(req.Messages)),
+			slog.Duration("latency", time.Since(start)),
+		}, usageLogAttrs(result.Usage)...)...,
+	)
+	return result, nil
+}
+
+// CreateChatCompletionStream sends a streaming chat completion request to the OpenAI API.
+// It calls onDelta with each piece of answer text as it arrives and assembles the chunks,
+// including tool calls and usage, into a single response.
+func (o *OpenAIClient) CreateChatCompletionStream(ctx context.Context, req ChatCompletionRequest, onDelta func(delta string)) (ChatCompletionResponse, error) {
+	openaiReq := mapToOpenAIRequest(req)
+	openaiReq.Stream = true
+	openaiReq.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+
+	logger := loggerOrDiscard(o.logger)
+	start := time.Now()
+	stream, err := o.client.CreateChatCompletionStream(ctx, openaiReq)
+	if err != nil {
+		logger.ErrorContext(ctx, "openai chat completion stream failed",
+			slog.String("model", req.Model),
+			slog.Duration("latency", time.Since(start)),
+			slog.Any("error", err),
+		)
+		return ChatCompletionResponse{}, fmt.Errorf("openai error: %w", err)
+	}
+	defer stream.Close()
+
+	var acc openAIStreamAccumulator
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			logger.ErrorContext(ctx, "openai chat completion stream failed",
+				slog.String("model", req.Model),
+				slog.Duration("latency", time.Since(start)),
+				slog.Any("error", err),
+			)
+			return ChatCompletionResponse{}, fmt.Errorf("openai stream error: %w", err)
+		}
+		if delta := acc.add(chunk); delta != "" && onDelta != nil {
+			onDelta(delta)
+		}
+	}
+
+	result := acc.response()
+	logger.DebugContext(ctx, "openai chat completion stream",
+		append([]any{
+			slog.String("model", req.Model),
+			slog.Int("messages", len(req.Messages)),
+			slog.Duration("latency", time.Since(start)),
+		}, usageLogAttrs(result.Usage)...)...,
+	)
+	return result, nil
+}
+
+// openAIStreamAccumulator assembles the chunks of a streamed OpenAI response.
+// Only the first choice is kept, which is the only one the agents use.
+type openAIStreamAccumulator struct {
+	role         string
+	content      strings.Builder
+	toolCalls    []openai.ToolCall
+	finishReason openai.FinishReason
+	usage        Usage
+}
+
+// add merges a chunk into the response and returns the answer text it carried.
+func (acc *openAIStreamAccumulator) add(chunk openai.ChatCompletionStreamResponse) string {
+	if chunk.Usage != nil {
+		acc.usage = mapFromOpenAIUsage(*chunk.Usage)
+	}
+	if len(chunk.Choices) == 0 {
+		return ""
+	}
+
+	choice := chunk.Choices[0]
+	if choice.Delta.Role != "" {
+		acc.role = choice.Delta.Role
+	}
+	if choice.FinishReason != "" {
+		acc.finishReason = choice.FinishReason
+	}
+	for _, delta := range choice.Delta.ToolCalls {
+		index := len(acc.toolCalls)
+		if delta.Index != nil {
+			index = *delta.Index
+		}
+		for len(acc.toolCalls) <= index {
+			acc.toolCalls = append(acc.toolCalls, openai.ToolCall{Type: openai.ToolTypeFunction})
+		}
+		call := &acc.toolCalls[index]
+		if delta.ID != "" {
+			call.ID = delta.ID
+		}
+		call.Function.Name += delta.Function.Name
+		call.Function.Arguments += delta.Function.Arguments
+	}
+	acc.content.WriteString(choice.Delta.Content)
+	return choice.Delta.Content
+}
+
+// response returns the assembled response.
+func (acc *openAIStreamAccumulator) response() ChatCompletionResponse {
+	role := acc.role
+	if role == "" {
+		role = RoleAssistant
+	}
+	return ChatCompletionResponse{
+		Choices: []Choice{{
+			Message: Message{
+				Role:      role,
+				Content:   acc.content.String(),
+				ToolCalls: mapFromOpenAIToolCalls(acc.toolCalls),
+			},
+			FinishReason: string(acc.finishReason),
+		}},
+		Usage: acc.usage,
+	}
 }
@@ -3,6 +3,7 @@ package syndicate
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -124,9 +125,10 @@ func TestMapFromOpenAIResponse(t *testing.T) {
 			},
 		},
 		Usage: openai.Usage{
-			PromptTokens:     10,
-			CompletionTokens: 20,
-			TotalTokens:      30,
+			PromptTokens:        10,
+			CompletionTokens:    20,
+			TotalTokens:         30,
+			PromptTokensDetails: &openai.PromptTokensDetails{CachedTokens: 8},
 		},
 	}
 	internalResp := mapFromOpenAIResponse(fakeResp)
@@ -159,7 +161,8 @@ func TestMapFromOpenAIResponse(t *testing.T) {
 	if string(tc.Args) != `{"arg":"value"}` {
 		t.Errorf("se esperaba tool call Args '{\"arg\":\"value\"}', se obtuvo '%s'", string(tc.Args))
 	}
-	if internalResp.Usage.PromptTokens != 10 || internalResp.Usage.CompletionTokens != 20 || internalResp.Usage.TotalTokens != 30 {
+	if internalResp.Usage.PromptTokens != 10 || internalResp.Usage.CompletionTokens != 20 || internalResp.Usage.TotalTokens != 30 ||
+		internalResp.Usage.CachedPromptTokens != 8 {
 		t.Errorf("usage inesperado: %+v", internalResp.Usage)
 	}
 }
@@ -247,3 +250,53 @@ func TestCreateChatCompletion(t *testing.T) {
 		t.Errorf("usage inesperado: %+v", resp.Usage)
 	}
 }
+
+// TestCreateChatCompletionStream verifica que los fragmentos del stream se reporten y se ensamblen en una respuesta.
+func TestCreateChatCompletionStream(t *testing.T) {
+	chunks := []string{
+		`{"choices":[{"index":0,"delta":{"role":"assistant","content":"Hola"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":" mundo"}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"c1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Lima\"}"}}]},"finish_reason":"tool_calls"}]}`,
+		`{"choices":[],"usage":{"prompt_tokens":5,"completion_tokens":7,"total_tokens":12}}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["stream"] != true {
+			t.Errorf("se esperaba un request con stream, se obtuvo %v", body["stream"])
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	client := &OpenAIClient{client: openai.NewClientWithConfig(openai.DefaultAzureConfig("test-api-key", server.URL))}
+
+	var deltas []string
+	resp, err := client.CreateChatCompletionStream(context.Background(), ChatCompletionRequest{
+		Model:    "gpt-4o",
+		Messages: []Message{{Role: RoleUser, Content: "Hola"}},
+	}, func(delta string) {
+		deltas = append(deltas, delta)
+	})
+	if err != nil {
+		t.Fatalf("CreateChatCompletionStream retornó error: %v", err)
+	}
+	if strings.Join(deltas, "|") != "Hola| mundo" {
+		t.Errorf("fragmentos inesperados: %q", deltas)
+	}
+	choice := resp.Choices[0]
+	if choice.Message.Content != "Hola mundo" || choice.Message.Role != RoleAssistant || choice.FinishReason != FinishReasonToolCalls {
+		t.Errorf("respuesta ensamblada inesperada: %+v", choice)
+	}
+	if len(choice.Message.ToolCalls) != 1 || choice.Message.ToolCalls[0].ID != "c1" || string(choice.Message.ToolCalls[0].Args) != `{"city":"Lima"}` {
+		t.Errorf("tool calls inesperadas: %+v", choice.Message.ToolCalls)
+	}
+	if resp.Usage.TotalTokens != 12 {
+		t.Errorf("usage inesperado: %+v", resp.Usage)
+	}
+}
@@ -0,0 +1,85 @@
+// Package otelsyndicate adapts OpenTelemetry tracers to the syndicate.Tracer interface,
+// so agent and syndicate spans are exported through any OpenTelemetry pipeline.
+package otelsyndicate
+
+import (
+	"context"
+	"fmt"
+
+	syndicate "github.com/Dieg0Code/syndicate-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer wraps an OpenTelemetry trace.Tracer and implements syndicate.Tracer.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer creates a syndicate.Tracer backed by the given OpenTelemetry tracer.
+//
+// Example:
+//
+//	tracer := otelsyndicate.NewTracer(otel.Tracer("my-service"))
+//	agent, err := syndicate.NewAgent(
+//		syndicate.WithTracer(tracer),
+//		// ... other options
+//	)
+func NewTracer(tracer trace.Tracer) *Tracer {
+	return &Tracer{tracer: tracer}
+}
+
+// Start begins an OpenTelemetry span nested under the span stored in ctx, if any.
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...syndicate.Attribute) (context.Context, syndicate.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(convertAttributes(attrs)...))
+	return ctx, &otelSpan{span: span}
+}
+
+// otelSpan adapts trace.Span to syndicate.Span.
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s *otelSpan) SetAttributes(attrs ...syndicate.Attribute) {
+	s.span.SetAttributes(convertAttributes(attrs)...)
+}
+
+func (s *otelSpan) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s *otelSpan) End() {
+	s.span.End()
+}
+
+// convertAttributes maps syndicate attributes to OpenTelemetry key/values.
+// Values of unsupported types are recorded using their fmt representation.
+func convertAttributes(attrs []syndicate.Attribute) []attribute.KeyValue {
+	result := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case string:
+			result = append(result, attribute.String(attr.Key, v))
+		case int:
+			result = append(result, attribute.Int(attr.Key, v))
+		case int64:
+			result = append(result, attribute.Int64(attr.Key, v))
+		case float64:
+			result = append(result, attribute.Float64(attr.Key, v))
+		case float32:
+			result = append(result, attribute.Float64(attr.Key, float64(v)))
+		case bool:
+			result = append(result, attribute.Bool(attr.Key, v))
+		case []string:
+			result = append(result, attribute.StringSlice(attr.Key, v))
+		default:
+			result = append(result, attribute.String(attr.Key, fmt.Sprintf("%v", v)))
+		}
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package otelsyndicate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	syndicate "github.com/Dieg0Code/syndicate-go"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestTracerExportsNestedSpans verifica que los spans se exporten anidados y con sus atributos.
+func TestTracerExportsNestedSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := NewTracer(provider.Tracer("test"))
+
+	ctx, parent := tracer.Start(context.Background(), syndicate.SpanChat, syndicate.Attr(syndicate.AttrAgentName, "agent"))
+	_, child := tracer.Start(ctx, syndicate.SpanToolCall, syndicate.Attr(syndicate.AttrToolName, "lookup"))
+	child.SetAttributes(syndicate.Attr(syndicate.AttrTotalTokens, 42), syndicate.Attr("custom", struct{}{}))
+	child.RecordError(errors.New("boom"))
+	child.End()
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("se esperaban 2 spans, se obtuvieron %d", len(spans))
+	}
+
+	toolSpan, chatSpan := spans[0], spans[1]
+	if toolSpan.Parent.SpanID() != chatSpan.SpanContext.SpanID() {
+		t.Error("el span de herramienta debería colgar del span de chat")
+	}
+	if toolSpan.Status.Code != codes.Error {
+		t.Errorf("se esperaba estado de error, se obtuvo %v", toolSpan.Status.Code)
+	}
+
+	attrs := make(map[string]string)
+	for _, kv := range toolSpan.Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs[syndicate.AttrToolName] != "lookup" || attrs[syndicate.AttrTotalTokens] != "42" || attrs["custom"] != "{}" {
+		t.Errorf("atributos inesperados: %v", attrs)
+	}
+}
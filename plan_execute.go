@@ -0,0 +1,242 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Limits of the plan-and-execute strategy.
+const (
+	defaultMaxReplans = 2
+	maxPlanSteps      = 10
+)
+
+// plannerInstructions asks the model for a plan; %s is replaced by the tool list.
+const plannerInstructions = `Before answering, plan how to fulfil the user's latest request. Break it into a short list of concrete steps (at most %d) that can each be carried out with the following tools or with reasoning alone:
+
+%s
+
+Do not carry out the steps. Return only the plan.`
+
+// planOutput is the response format requested from the planner.
+type planOutput struct {
+	Steps []string `json:"steps" description:"The steps to carry out, in order"`
+}
+
+// planAndExecuteStrategy implements PlanAndExecuteStrategy.
+type planAndExecuteStrategy struct {
+	maxReplans int
+}
+
+// PlanAndExecuteStrategy returns a strategy where the model first writes a plan (a list of steps) and
+// then executes each step with the native tool-call loop. When a step fails, the model is asked for a
+// new plan for the remaining work, up to maxReplans times; a negative value uses the default of 2.
+// Once every step is done, the model writes the final answer from the step results.
+//
+// The plan is recorded in ChatResult.Plan; only the user's message and the final answer are stored in memory.
+func PlanAndExecuteStrategy(maxReplans int) Strategy {
+	if maxReplans < 0 {
+		maxReplans = defaultMaxReplans
+	}
+	return planAndExecuteStrategy{maxReplans: maxReplans}
+}
+
+func (s planAndExecuteStrategy) run(ctx context.Context, a *agent, t *turn, messages []Message) error {
+	pending, err := s.plan(ctx, a, t, messages, "")
+	if err != nil {
+		return err
+	}
+
+	var done []PlanStep
+	for len(pending) > 0 {
+		t.result.Plan = planSteps(done, pending)
+
+		description := pending[0]
+		result, err := s.executeStep(ctx, a, t, done, description)
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			done = append(done, PlanStep{Description: description, Status: PlanStepFailed, Error: err.Error()})
+			t.result.Plan = planSteps(done, nil)
+			if t.result.Replans >= s.maxReplans {
+				return fmt.Errorf("plan step %q failed: %w", description, err)
+			}
+			t.result.Replans++
+
+			a.logger.WarnContext(ctx, "plan step failed, re-planning",
+				slog.String("agent", a.name),
+				slog.String("step", description),
+				slog.Any("error", err),
+			)
+			pending, err = s.plan(ctx, a, t, messages, replanRequest(done))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if t.result.Handoff != nil {
+			return nil
+		}
+
+		done = append(done, PlanStep{Description: description, Status: PlanStepCompleted, Result: result})
+		pending = pending[1:]
+	}
+	t.result.Plan = planSteps(done, nil)
+
+	return s.synthesize(ctx, a, t, messages, done)
+}
+
+// plan asks the model for the list of steps; feedback describes the progress so far when re-planning.
+func (s planAndExecuteStrategy) plan(ctx context.Context, a *agent, t *turn, messages []Message, feedback string) ([]string, error) {
+	format, err := newJSONResponseFormat("plan", planOutput{})
+	if err != nil {
+		return nil, err
+	}
+
+	messages = a.withInstructions(t, messages, fmt.Sprintf(plannerInstructions, maxPlanSteps, describeTools(t.tools)))
+	if feedback != "" {
+		messages = append(messages, Message{Role: RoleUser, Content: feedback})
+	}
+
+	a.mutex.RLock()
+	req := ChatCompletionRequest{
+		Model:          a.model,
+		Messages:       messages,
+		Temperature:    a.temperature,
+		ResponseFormat: format,
+	}
+	a.mutex.RUnlock()
+
+	// The plan is not part of the answer, so it is not reported as content deltas.
+	choice, err := a.completeWith(ctx, t, a.client, req, false)
+	if err != nil {
+		return nil, fmt.Errorf("planning failed: %w", err)
+	}
+
+	var plan planOutput
+	if err := json.Unmarshal([]byte(trimCodeFence(choice.Message.Content)), &plan); err != nil {
+		return nil, fmt.Errorf("invalid plan: %w", err)
+	}
+
+	steps := make([]string, 0, len(plan.Steps))
+	for _, step := range plan.Steps {
+		if step = strings.TrimSpace(step); step != "" {
+			steps = append(steps, step)
+		}
+	}
+	if len(steps) > maxPlanSteps {
+		steps = steps[:maxPlanSteps]
+	}
+	return steps, nil
+}
+
+// executeStep runs one step of the plan with the native tool-call loop and returns its result.
+// The step works on a scratch copy of the conversation, so its tool calls are not stored in memory;
+// its rounds, usage and tool calls are added to the turn result.
+func (s planAndExecuteStrategy) executeStep(ctx context.Context, a *agent, t *turn, done []PlanStep, description string) (string, error) {
+	scratch := NewSimpleMemory()
+	for _, msg := range t.memory.Get() {
+		scratch.Add(msg)
+	}
+	scratch.Add(Message{Role: RoleUser, Content: stepRequest(done, description)})
+
+	step := &turn{
+		memory:       scratch,
+		systemPrompt: t.systemPrompt,
+		examples:     t.examples,
+		tools:        t.tools,
+		extraTools:   t.extraTools,
+		events:       t.events,
+		scratch:      true,
+		result:       &ChatResult{AgentName: a.name},
+	}
+
+	a.mutex.RLock()
+	messages := a.prepareMessages(step)
+	a.mutex.RUnlock()
+
+	err := a.processWithTools(ctx, step, messages)
+
+	t.mutex.Lock()
+	t.result.Rounds += step.result.Rounds
+	t.result.Usage = t.result.Usage.Add(step.result.Usage)
+	t.result.ToolCalls = append(t.result.ToolCalls, step.result.ToolCalls...)
+	t.result.FinishReason = step.result.FinishReason
+	if step.result.Handoff != nil && t.result.Handoff == nil {
+		t.result.Handoff = step.result.Handoff
+	}
+	t.mutex.Unlock()
+
+	if err != nil {
+		return "", err
+	}
+	return step.result.Content, nil
+}
+
+// synthesize asks the model for the final answer from the results of the plan.
+func (s planAndExecuteStrategy) synthesize(ctx context.Context, a *agent, t *turn, messages []Message, done []PlanStep) error {
+	messages = append(messages, Message{
+		Role:    RoleUser,
+		Content: fmt.Sprintf("%s\nUsing these results, write the final answer to my request.", completedStepsText(done)),
+	})
+
+	a.mutex.RLock()
+	req := ChatCompletionRequest{
+		Model:          a.model,
+		Messages:       messages,
+		Temperature:    a.temperature,
+		ResponseFormat: a.responseFormat,
+	}
+	a.mutex.RUnlock()
+
+	choice, err := a.complete(ctx, t, req)
+	if err != nil {
+		return err
+	}
+
+	return a.answer(ctx, t, choice.Message.Content)
+}
+
+// planSteps lists the executed steps followed by the pending ones.
+func planSteps(done []PlanStep, pending []string) []PlanStep {
+	steps := make([]PlanStep, 0, len(done)+len(pending))
+	steps = append(steps, done...)
+	for _, description := range pending {
+		steps = append(steps, PlanStep{Description: description, Status: PlanStepPending})
+	}
+	return steps
+}
+
+// completedStepsText describes the executed steps and their outcomes.
+func completedStepsText(done []PlanStep) string {
+	if len(done) == 0 {
+		return "No steps have been carried out yet.\n"
+	}
+	var sb strings.Builder
+	sb.WriteString("Steps carried out so far:\n")
+	for i, step := range done {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, step.Description)
+		if step.Status == PlanStepFailed {
+			fmt.Fprintf(&sb, "   Failed: %s\n", step.Error)
+		} else {
+			fmt.Fprintf(&sb, "   Result: %s\n", step.Result)
+		}
+	}
+	return sb.String()
+}
+
+// stepRequest asks the executor to carry out a single step.
+func stepRequest(done []PlanStep, description string) string {
+	return fmt.Sprintf("%s\nCarry out only the next step of the plan, using tools if needed, and reply with its result.\nNext step: %s",
+		completedStepsText(done), description)
+}
+
+// replanRequest asks the planner for a new plan after a failed step.
+func replanRequest(done []PlanStep) string {
+	return fmt.Sprintf("%s\nThe last step failed. Write a new plan with only the steps still needed to fulfil the request.",
+		completedStepsText(done))
+}
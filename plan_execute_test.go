@@ -0,0 +1,128 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestPlanAndExecuteStrategy verifica la planificación, la ejecución de pasos con herramientas y la respuesta final.
+func TestPlanAndExecuteStrategy(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse(`{"steps":["buscar el precio","calcular el total"]}`),
+		toolCallResponse(ToolCall{ID: "c1", Name: "price", Args: json.RawMessage(`{}`)}),
+		textResponse("el precio es 10"),
+		textResponse("el total es 20"),
+		textResponse("Debes pagar 20"),
+	}}
+	tool := &fakeTool{
+		def:      ToolDefinition{Name: "price"},
+		execFunc: func(args json.RawMessage) (interface{}, error) { return 10, nil },
+	}
+	mem := NewSimpleMemory()
+
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("planner"),
+		WithMemory(mem),
+		WithModel("gpt-4o"),
+		WithTool(tool),
+		WithStrategy(PlanAndExecuteStrategy(-1)),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	result, err := agent.(DetailedAgent).ChatDetailed(context.Background(), WithUserName("user"), WithInput("¿Cuánto pago por dos?"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if result.Content != "Debes pagar 20" {
+		t.Errorf("respuesta inesperada: %s", result.Content)
+	}
+	if len(result.Plan) != 2 || result.Plan[0].Status != PlanStepCompleted || result.Plan[1].Result != "el total es 20" {
+		t.Errorf("plan inesperado: %+v", result.Plan)
+	}
+	if result.Rounds != 5 || len(result.ToolCalls) != 1 {
+		t.Errorf("se esperaban 5 rondas y 1 llamada a herramienta, se obtuvo %d y %d", result.Rounds, len(result.ToolCalls))
+	}
+
+	requests := client.Requests()
+	if requests[0].ResponseFormat == nil || len(requests[0].Tools) != 0 {
+		t.Error("el planificador debía pedir JSON sin herramientas")
+	}
+	if len(requests[1].Tools) != 1 {
+		t.Error("el ejecutor debía recibir las herramientas")
+	}
+	if !strings.Contains(requests[3].Messages[len(requests[3].Messages)-1].Content, "el precio es 10") {
+		t.Error("el segundo paso debía conocer el resultado del primero")
+	}
+
+	if msgs := mem.Get(); len(msgs) != 2 || msgs[1].Content != "Debes pagar 20" {
+		t.Errorf("la memoria solo debía guardar la pregunta y la respuesta final: %+v", msgs)
+	}
+}
+
+// TestPlanAndExecuteReplan verifica que un paso fallido provoque una nueva planificación.
+func TestPlanAndExecuteReplan(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse(`{"steps":["usar la herramienta rota"]}`),
+		toolCallResponse(ToolCall{ID: "c1", Name: "broken", Args: json.RawMessage(`{}`)}),
+		textResponse(`{"steps":["responder sin herramientas"]}`),
+		textResponse("hecho"),
+		textResponse("respuesta final"),
+	}}
+	tool := &fakeTool{
+		def:      ToolDefinition{Name: "broken"},
+		execFunc: func(args json.RawMessage) (interface{}, error) { return nil, errors.New("fallo") },
+	}
+
+	agent, _ := NewAgent(
+		WithClient(client),
+		WithName("planner"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithTool(tool),
+		WithStrategy(PlanAndExecuteStrategy(1)),
+	)
+
+	result, err := agent.(DetailedAgent).ChatDetailed(context.Background(), WithUserName("user"), WithInput("hazlo"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if result.Replans != 1 || len(result.Plan) != 2 || result.Plan[0].Status != PlanStepFailed || result.Plan[1].Status != PlanStepCompleted {
+		t.Errorf("plan inesperado: replans=%d %+v", result.Replans, result.Plan)
+	}
+	replan := client.Requests()[2].Messages
+	if !strings.Contains(replan[len(replan)-1].Content, "Failed:") {
+		t.Error("el nuevo plan debía conocer el paso fallido")
+	}
+}
+
+// TestPlanAndExecuteReplanLimit verifica que se devuelva el error al agotar las replanificaciones.
+func TestPlanAndExecuteReplanLimit(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse(`{"steps":["usar la herramienta rota"]}`),
+		toolCallResponse(ToolCall{ID: "c1", Name: "broken", Args: json.RawMessage(`{}`)}),
+	}}
+	tool := &fakeTool{
+		def:      ToolDefinition{Name: "broken"},
+		execFunc: func(args json.RawMessage) (interface{}, error) { return nil, errors.New("fallo") },
+	}
+
+	agent, _ := NewAgent(
+		WithClient(client),
+		WithName("planner"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithTool(tool),
+		WithStrategy(PlanAndExecuteStrategy(0)),
+	)
+
+	_, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("hazlo"))
+	if err == nil || !strings.Contains(err.Error(), "plan step") {
+		t.Errorf("se esperaba el error del paso, se obtuvo: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package syndicate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// PromptContext describes the chat request a system prompt is rendered for.
+type PromptContext struct {
+	AgentName string
+	UserName  string
+	Input     string
+	SessionID string
+	Metadata  map[string]any // Values set with WithMetadata; nil when none were given.
+}
+
+// SystemPromptProvider renders the system prompt for a chat request.
+// It is called once at the start of every Chat, and its error aborts the chat.
+type SystemPromptProvider func(ctx context.Context, pc PromptContext) (string, error)
+
+// WithSystemPromptProvider renders the system prompt dynamically on every chat,
+// for example to include the current date, the user's profile or retrieved documents.
+// When set, it takes precedence over the static prompt from WithSystemPrompt.
+func WithSystemPromptProvider(provider SystemPromptProvider) AgentOption {
+	return func(a *agent) error {
+		if provider == nil {
+			return errors.New("system prompt provider cannot be nil")
+		}
+		a.promptProvider = provider
+		return nil
+	}
+}
+
+// WithSystemPromptBuilder renders the system prompt on every chat from a PromptBuilder.
+// It is a convenience over WithSystemPromptProvider for prompts built with NewPromptBuilder.
+//
+// Example:
+//
+//	syndicate.WithSystemPromptBuilder(func(ctx context.Context, pc syndicate.PromptContext) (*syndicate.PromptBuilder, error) {
+//		return syndicate.NewPromptBuilder().
+//			CreateSection("Context").
+//			AddTextF("Context", map[string]any{"date": time.Now().Format(time.DateOnly), "user": pc.UserName}), nil
+//	})
+func WithSystemPromptBuilder(build func(ctx context.Context, pc PromptContext) (*PromptBuilder, error)) AgentOption {
+	return func(a *agent) error {
+		if build == nil {
+			return errors.New("system prompt builder cannot be nil")
+		}
+		a.promptProvider = func(ctx context.Context, pc PromptContext) (string, error) {
+			builder, err := build(ctx, pc)
+			if err != nil {
+				return "", err
+			}
+			if builder == nil {
+				return "", nil
+			}
+			return builder.Build(), nil
+		}
+		return nil
+	}
+}
+
+// renderSystemPrompt returns the system prompt for the request, calling the provider if one is configured.
+func (a *agent) renderSystemPrompt(ctx context.Context, req *chatRequest) (string, error) {
+	a.mutex.RLock()
+	provider := a.promptProvider
+	prompt := a.systemPrompt
+	a.mutex.RUnlock()
+
+	if provider == nil {
+		return prompt, nil
+	}
+
+	prompt, err := provider(ctx, PromptContext{
+		AgentName: a.name,
+		UserName:  req.userName,
+		Input:     req.input,
+		SessionID: req.sessionID,
+		Metadata:  req.metadata,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error rendering system prompt: %w", err)
+	}
+	return prompt, nil
+}
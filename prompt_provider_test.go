@@ -0,0 +1,94 @@
+package syndicate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestSystemPromptProvider verifica que el prompt se renderice en cada chat con los datos del request.
+func TestSystemPromptProvider(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("uno"), textResponse("dos")}}
+	calls := 0
+
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("dynamic"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithSystemPrompt("estático"),
+		WithSystemPromptProvider(func(ctx context.Context, pc PromptContext) (string, error) {
+			calls++
+			return "usuario=" + pc.UserName + " locale=" + pc.Metadata["locale"].(string) + " sesión=" + pc.SessionID, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	ctx := context.Background()
+	agent.Chat(ctx, WithUserName("ana"), WithInput("hola"), WithMetadata("locale", "es-CL"), WithSessionID("s1"))
+	agent.Chat(ctx, WithUserName("luis"), WithInput("hola"), WithMetadata("locale", "en-US"))
+
+	if calls != 2 {
+		t.Errorf("se esperaban 2 renderizados, se obtuvieron %d", calls)
+	}
+	reqs := client.Requests()
+	if got := reqs[0].Messages[0].Content; got != "usuario=ana locale=es-CL sesión=s1" {
+		t.Errorf("prompt inesperado en el primer chat: %q", got)
+	}
+	if got := reqs[1].Messages[0].Content; got != "usuario=luis locale=en-US sesión=" {
+		t.Errorf("prompt inesperado en el segundo chat: %q", got)
+	}
+}
+
+// TestSystemPromptBuilder verifica el uso de un PromptBuilder como proveedor.
+func TestSystemPromptBuilder(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("ok")}}
+	agent, _ := NewAgent(
+		WithClient(client),
+		WithName("dynamic"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithSystemPromptBuilder(func(ctx context.Context, pc PromptContext) (*PromptBuilder, error) {
+			return NewPromptBuilder().CreateSection("User").AddText("User", pc.UserName), nil
+		}),
+	)
+
+	if _, err := agent.Chat(context.Background(), WithUserName("ana"), WithInput("hola")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if got := client.Requests()[0].Messages[0].Content; !strings.Contains(got, "<User>\nana\n</User>") {
+		t.Errorf("prompt inesperado: %q", got)
+	}
+}
+
+// TestSystemPromptProviderError verifica que los errores del proveedor se propaguen sin tocar la memoria.
+func TestSystemPromptProviderError(t *testing.T) {
+	mem := NewSimpleMemory()
+	agent, _ := NewAgent(
+		WithClient(&fakeLLMClient{}),
+		WithName("dynamic"),
+		WithMemory(mem),
+		WithModel("gpt-4o"),
+		WithSystemPromptProvider(func(ctx context.Context, pc PromptContext) (string, error) {
+			return "", errors.New("perfil no disponible")
+		}),
+	)
+
+	_, err := agent.Chat(context.Background(), WithUserName("ana"), WithInput("hola"))
+	if err == nil || !strings.Contains(err.Error(), "perfil no disponible") {
+		t.Errorf("se esperaba el error del proveedor, se obtuvo: %v", err)
+	}
+	if len(mem.Get()) != 0 {
+		t.Error("no debería escribirse en memoria si falla el renderizado del prompt")
+	}
+
+	if _, err := NewAgent(WithSystemPromptProvider(nil)); err == nil {
+		t.Error("se esperaba error por proveedor nil")
+	}
+	if _, err := NewAgent(WithSystemPromptBuilder(nil)); err == nil {
+		t.Error("se esperaba error por builder nil")
+	}
+}
@@ -0,0 +1,178 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// defaultReActIterations is the number of thought/action cycles allowed when none is configured.
+const defaultReActIterations = 10
+
+// reactInstructions explains the ReAct format to the model; %s is replaced by the tool list.
+const reactInstructions = `Answer the user's request by reasoning step by step. You can use the following tools:
+
+%s
+
+Use exactly this format:
+
+Thought: your reasoning about what to do next
+Action: the name of the tool to use
+Action Input: the tool arguments as a JSON object
+
+You will then receive:
+
+Observation: the result of the tool
+
+Repeat Thought/Action/Observation as many times as needed. When you know the answer, reply with:
+
+Thought: your final reasoning
+Final Answer: the answer to the user`
+
+// reactStrategy implements ReActStrategy.
+type reactStrategy struct {
+	maxIterations int
+}
+
+// ReActStrategy returns a strategy where the model alternates written thoughts, tool actions and
+// observations (ReAct). Tools are described in the system prompt and invoked through plain text, so
+// it works with models that do not support native tool calls. maxIterations limits the number of
+// cycles per turn; zero or less uses the default of 10.
+//
+// The cycles are recorded in ChatResult.Steps; only the user's message and the final answer are stored in memory.
+func ReActStrategy(maxIterations int) Strategy {
+	if maxIterations <= 0 {
+		maxIterations = defaultReActIterations
+	}
+	return reactStrategy{maxIterations: maxIterations}
+}
+
+func (s reactStrategy) run(ctx context.Context, a *agent, t *turn, messages []Message) error {
+	messages = a.withInstructions(t, messages, fmt.Sprintf(reactInstructions, describeTools(t.tools)))
+
+	for i := 1; i <= s.maxIterations; i++ {
+		a.mutex.RLock()
+		req := ChatCompletionRequest{
+			Model:       a.model,
+			Messages:    messages,
+			Temperature: a.temperature,
+		}
+		a.mutex.RUnlock()
+
+		// The raw cycle holds the model's reasoning; only the final answer is reported as content.
+		choice, err := a.completeWith(ctx, t, a.client, req, false)
+		if err != nil {
+			return err
+		}
+
+		step, answer, final := parseReActStep(choice.Message.Content)
+		if final {
+			t.result.Steps = append(t.result.Steps, step)
+			t.events.emit(ctx, Event{Type: EventContentDelta, Round: t.result.Rounds, Delta: answer})
+			return a.answer(ctx, t, answer)
+		}
+
+		step.Observation, err = a.observe(ctx, t, i, step)
+		if err != nil {
+			return err
+		}
+		t.result.Steps = append(t.result.Steps, step)
+		if t.result.Handoff != nil {
+			return nil
+		}
+
+		messages = append(messages,
+			Message{Role: RoleAssistant, Content: formatReActStep(step), Name: a.name},
+			Message{Role: RoleUser, Content: "Observation: " + step.Observation},
+		)
+	}
+
+	return fmt.Errorf("no final answer after %d ReAct iterations", s.maxIterations)
+}
+
+// observe runs the action of a ReAct step and returns the observation for the model.
+// Mistakes the model can correct, such as an unknown tool, are reported as observations.
+func (a *agent) observe(ctx context.Context, t *turn, iteration int, step ReasoningStep) (string, error) {
+	if !t.offersTool(step.Action) {
+		names := make([]string, 0, len(t.tools))
+		for _, tool := range t.tools {
+			names = append(names, tool.Name)
+		}
+		return fmt.Sprintf("Error: unknown tool %q. Available tools: %s.", step.Action, strings.Join(names, ", ")), nil
+	}
+	if !json.Valid(step.ActionInput) {
+		return "Error: Action Input must be a valid JSON object.", nil
+	}
+
+	call := ToolCall{
+		ID:   fmt.Sprintf("react_%d", iteration),
+		Name: step.Action,
+		Args: step.ActionInput,
+	}
+	result, err := a.runToolCall(a.toolContext(ctx, t), t, call)
+	if err != nil {
+		return "", err
+	}
+	t.result.ToolCalls = append(t.result.ToolCalls, result)
+	return result.Result, nil
+}
+
+// parseReActStep extracts a ReAct step from a model response. It returns the final answer and true
+// when the response has no action; a response that does not follow the format is taken as the answer.
+func parseReActStep(content string) (ReasoningStep, string, bool) {
+	// Models sometimes invent the observation themselves; only the text before it is theirs.
+	if i := strings.Index(content, "\nObservation:"); i >= 0 {
+		content = content[:i]
+	}
+
+	var step ReasoningStep
+	actionIndex := strings.Index(content, "Action:")
+	finalIndex := strings.Index(content, "Final Answer:")
+	thoughtEnd := len(content)
+	if actionIndex >= 0 {
+		thoughtEnd = actionIndex
+	}
+	if finalIndex >= 0 && finalIndex < thoughtEnd {
+		thoughtEnd = finalIndex
+	}
+	step.Thought = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(content[:thoughtEnd]), "Thought:"))
+
+	if actionIndex < 0 || (finalIndex >= 0 && finalIndex < actionIndex) {
+		if finalIndex < 0 {
+			return step, strings.TrimSpace(content), true
+		}
+		return step, strings.TrimSpace(content[finalIndex+len("Final Answer:"):]), true
+	}
+
+	action := content[actionIndex+len("Action:"):]
+	input := ""
+	if i := strings.Index(action, "Action Input:"); i >= 0 {
+		input = action[i+len("Action Input:"):]
+		action = action[:i]
+	}
+	step.Action = strings.TrimSpace(action)
+	step.ActionInput = json.RawMessage(trimCodeFence(input))
+	if len(step.ActionInput) == 0 {
+		step.ActionInput = json.RawMessage("{}")
+	}
+	return step, "", false
+}
+
+// formatReActStep renders a step in the format the model is asked to use.
+func formatReActStep(step ReasoningStep) string {
+	return fmt.Sprintf("Thought: %s\nAction: %s\nAction Input: %s", step.Thought, step.Action, step.ActionInput)
+}
+
+// trimCodeFence removes surrounding whitespace and a Markdown code fence, if any.
+func trimCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```")
+	if i := strings.Index(s, "\n"); i >= 0 {
+		s = s[i+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "```"))
+}
@@ -0,0 +1,116 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestParseReActStep verifica el análisis de las respuestas en formato ReAct.
+func TestParseReActStep(t *testing.T) {
+	step, _, final := parseReActStep("Thought: necesito el clima\nAction: weather\nAction Input: ```json\n{\"city\":\"Lima\"}\n```\nObservation: inventada")
+	if final {
+		t.Fatal("no se esperaba una respuesta final")
+	}
+	if step.Thought != "necesito el clima" || step.Action != "weather" || string(step.ActionInput) != `{"city":"Lima"}` {
+		t.Errorf("paso inesperado: %+v", step)
+	}
+
+	step, answer, final := parseReActStep("Thought: ya lo sé\nFinal Answer: 20 grados")
+	if !final || answer != "20 grados" || step.Thought != "ya lo sé" {
+		t.Errorf("respuesta final inesperada: %+v %q %v", step, answer, final)
+	}
+
+	if _, answer, final := parseReActStep("respuesta libre"); !final || answer != "respuesta libre" {
+		t.Errorf("se esperaba tomar el texto libre como respuesta final: %q %v", answer, final)
+	}
+}
+
+// TestReActStrategy verifica el ciclo pensamiento/acción/observación sin herramientas nativas.
+func TestReActStrategy(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse("Thought: uso una herramienta inexistente\nAction: missing\nAction Input: {}"),
+		textResponse("Thought: consulto el clima\nAction: weather\nAction Input: {\"city\":\"Lima\"}"),
+		textResponse("Thought: ya tengo el dato\nFinal Answer: Hace 20 grados en Lima"),
+	}}
+	var received string
+	tool := &fakeTool{
+		def: ToolDefinition{Name: "weather", Description: "Returns the weather"},
+		execFunc: func(args json.RawMessage) (interface{}, error) {
+			received = string(args)
+			return "20C", nil
+		},
+	}
+	mem := NewSimpleMemory()
+
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("reactAgent"),
+		WithSystemPrompt("Eres un asistente."),
+		WithMemory(mem),
+		WithModel("gpt-4o"),
+		WithTool(tool),
+		WithStrategy(ReActStrategy(0)),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	result, err := agent.(DetailedAgent).ChatDetailed(context.Background(), WithUserName("user"), WithInput("¿Clima en Lima?"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if result.Content != "Hace 20 grados en Lima" {
+		t.Errorf("respuesta inesperada: %s", result.Content)
+	}
+	if received != `{"city":"Lima"}` {
+		t.Errorf("la herramienta recibió argumentos inesperados: %s", received)
+	}
+	if len(result.Steps) != 3 || !strings.Contains(result.Steps[0].Observation, "unknown tool") || result.Steps[1].Observation != `"20C"` {
+		t.Errorf("pasos inesperados: %+v", result.Steps)
+	}
+
+	requests := client.Requests()
+	if len(requests[0].Tools) != 0 {
+		t.Error("la estrategia ReAct no debía enviar herramientas nativas")
+	}
+	system := requests[0].Messages[0].Content
+	if !strings.HasPrefix(system, "Eres un asistente.") || !strings.Contains(system, "- weather: Returns the weather") {
+		t.Errorf("prompt de sistema inesperado: %s", system)
+	}
+	last := requests[2].Messages[len(requests[2].Messages)-1]
+	if last.Content != `Observation: "20C"` {
+		t.Errorf("observación inesperada: %+v", last)
+	}
+
+	if msgs := mem.Get(); len(msgs) != 2 || msgs[1].Content != "Hace 20 grados en Lima" {
+		t.Errorf("la memoria solo debía guardar la pregunta y la respuesta final: %+v", msgs)
+	}
+}
+
+// TestReActStrategyMaxIterations verifica que se falle al agotar las iteraciones.
+func TestReActStrategyMaxIterations(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse("Thought: sigo\nAction: missing\nAction Input: {}"),
+	}}
+	agent, _ := NewAgent(
+		WithClient(client),
+		WithName("reactAgent"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithStrategy(ReActStrategy(1)),
+	)
+
+	_, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("hola"))
+	if err == nil || !strings.Contains(err.Error(), "no final answer") {
+		t.Errorf("se esperaba un error por iteraciones agotadas, se obtuvo: %v", err)
+	}
+}
+
+// TestWithStrategyNil verifica que no se acepte una estrategia nula.
+func TestWithStrategyNil(t *testing.T) {
+	if _, err := NewAgent(WithClient(&recordingLLMClient{}), WithName("a"), WithMemory(NewSimpleMemory()), WithModel("m"), WithStrategy(nil)); err == nil {
+		t.Error("se esperaba un error con una estrategia nula")
+	}
+}
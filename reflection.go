@@ -0,0 +1,246 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// Reflection defaults.
+const (
+	defaultReflectionThreshold = 8
+	defaultMaxRevisions        = 2
+)
+
+// defaultCriticRubric is the rubric used when none is configured.
+const defaultCriticRubric = `Judge whether the answer fully and correctly fulfils the request.
+Consider accuracy, completeness, structure and clarity, and whether it follows any instructions in the request.`
+
+// criticInstructions wraps the rubric; %s is replaced by the rubric.
+const criticInstructions = `You are a strict reviewer. Score the answer to the request from 0 (unusable) to 10 (nothing to improve) using this rubric:
+
+%s
+
+Give concrete, actionable feedback on what must change to raise the score.`
+
+// revisionRequest asks the agent to revise its draft; the arguments are the score and the feedback.
+const revisionRequest = `A reviewer scored your answer %.1f/10 with this feedback:
+
+%s
+
+Rewrite your answer addressing the feedback. Reply with the complete revised answer only.`
+
+// critiqueOutput is the response format requested from the critic.
+type critiqueOutput struct {
+	Score    float64 `json:"score" description:"Score of the answer from 0 to 10"`
+	Feedback string  `json:"feedback" description:"What must change to improve the answer"`
+}
+
+// reflection holds the configuration of the reflection loop.
+type reflection struct {
+	client       LLMClient // Critic client; nil uses the agent's client.
+	model        string    // Critic model; empty uses the agent's model.
+	rubric       string
+	threshold    float64
+	maxRevisions int
+	keepDrafts   bool
+}
+
+// ReflectionOption configures the reflection loop enabled with WithReflection.
+type ReflectionOption func(*reflection) error
+
+// WithReflection enables a reflection stage: after the agent drafts an answer, a critic scores it
+// and the agent revises it until the score reaches the threshold or the revision limit is hit.
+// The critique rounds are recorded in ChatResult.Critiques. By default only the final answer is
+// stored in memory; see WithDraftsInMemory.
+//
+// Example:
+//
+//	agent, err := syndicate.NewAgent(
+//		syndicate.WithReflection(
+//			syndicate.WithCriticRubric("The report must cite a source for every figure."),
+//			syndicate.WithReflectionThreshold(9),
+//		),
+//		// ... other options
+//	)
+func WithReflection(options ...ReflectionOption) AgentOption {
+	return func(a *agent) error {
+		r := &reflection{
+			rubric:       defaultCriticRubric,
+			threshold:    defaultReflectionThreshold,
+			maxRevisions: defaultMaxRevisions,
+		}
+		for _, option := range options {
+			if err := option(r); err != nil {
+				return fmt.Errorf("failed to apply reflection option: %w", err)
+			}
+		}
+		a.reflection = r
+		return nil
+	}
+}
+
+// WithCritic sets the client and model used to score answers. A nil client uses the agent's
+// client and an empty model uses the agent's model.
+func WithCritic(client LLMClient, model string) ReflectionOption {
+	return func(r *reflection) error {
+		r.client = client
+		r.model = model
+		return nil
+	}
+}
+
+// WithCriticRubric sets the criteria the critic scores answers against.
+func WithCriticRubric(rubric string) ReflectionOption {
+	return func(r *reflection) error {
+		if rubric == "" {
+			return errors.New("rubric cannot be empty")
+		}
+		r.rubric = rubric
+		return nil
+	}
+}
+
+// WithReflectionThreshold sets the score, from 0 to 10, an answer needs to be accepted. It defaults to 8.
+func WithReflectionThreshold(score float64) ReflectionOption {
+	return func(r *reflection) error {
+		if score < 0 || score > 10 {
+			return errors.New("threshold must be between 0 and 10")
+		}
+		r.threshold = score
+		return nil
+	}
+}
+
+// WithMaxRevisions sets how many times an answer may be revised per turn. It defaults to 2.
+func WithMaxRevisions(max int) ReflectionOption {
+	return func(r *reflection) error {
+		if max < 1 {
+			return errors.New("max revisions must be at least 1")
+		}
+		r.maxRevisions = max
+		return nil
+	}
+}
+
+// WithDraftsInMemory stores every draft and critique in memory, not just the final answer.
+func WithDraftsInMemory() ReflectionOption {
+	return func(r *reflection) error {
+		r.keepDrafts = true
+		return nil
+	}
+}
+
+// reflect runs the critique and revision loop on a draft and returns the final answer.
+func (a *agent) reflect(ctx context.Context, t *turn, draft string) (string, error) {
+	for revision := 0; ; revision++ {
+		critique, err := a.critique(ctx, t, draft)
+		if err != nil {
+			return "", err
+		}
+		t.result.Critiques = append(t.result.Critiques, critique)
+
+		if critique.Passed || revision == a.reflection.maxRevisions {
+			return draft, nil
+		}
+
+		a.logger.DebugContext(ctx, "revising answer",
+			slog.String("agent", a.name),
+			slog.Float64("score", critique.Score),
+			slog.Int("revision", revision+1),
+		)
+		draft, err = a.revise(ctx, t, critique)
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// critique asks the critic to score a draft answer.
+func (a *agent) critique(ctx context.Context, t *turn, draft string) (Critique, error) {
+	r := a.reflection
+	format, err := newJSONResponseFormat("critique", critiqueOutput{})
+	if err != nil {
+		return Critique{}, err
+	}
+
+	a.mutex.RLock()
+	client, model := r.client, r.model
+	if client == nil {
+		client = a.client
+	}
+	if model == "" {
+		model = a.model
+	}
+	systemRole := getSystemRole(model)
+	a.mutex.RUnlock()
+
+	ctx, span := a.tracer.Start(ctx, SpanCritique, Attr(AttrAgentName, a.name), Attr(AttrModel, model))
+	defer span.End()
+
+	req := ChatCompletionRequest{
+		Model: model,
+		Messages: []Message{
+			{Role: systemRole, Content: fmt.Sprintf(criticInstructions, r.rubric)},
+			{Role: RoleUser, Content: fmt.Sprintf("Request:\n%s\n\nAnswer:\n%s", t.input, draft)},
+		},
+		ResponseFormat: format,
+	}
+
+	// The critique is a round of its own, but it is not reported as content and the finish reason
+	// of the turn stays the answer's.
+	finishReason := t.result.FinishReason
+	choice, err := a.completeWith(ctx, t, client, req, false)
+	t.result.FinishReason = finishReason
+	if err != nil {
+		err = fmt.Errorf("error in critique: %w", err)
+		span.RecordError(err)
+		return Critique{}, err
+	}
+
+	var output critiqueOutput
+	if err := json.Unmarshal([]byte(trimCodeFence(choice.Message.Content)), &output); err != nil {
+		err = fmt.Errorf("invalid critique: %w", err)
+		span.RecordError(err)
+		return Critique{}, err
+	}
+	span.SetAttributes(Attr(AttrCritiqueScore, output.Score))
+
+	return Critique{
+		Draft:    draft,
+		Score:    output.Score,
+		Feedback: output.Feedback,
+		Passed:   output.Score >= r.threshold,
+	}, nil
+}
+
+// revise asks the agent to rewrite the judged draft following the critic's feedback.
+func (a *agent) revise(ctx context.Context, t *turn, critique Critique) (string, error) {
+	draft := Message{Role: RoleAssistant, Content: critique.Draft, Name: a.name}
+	feedback := Message{Role: RoleUser, Content: fmt.Sprintf(revisionRequest, critique.Score, critique.Feedback), Name: "critic"}
+
+	var messages []Message
+	if a.reflection.keepDrafts {
+		if err := a.remember(ctx, t, draft); err != nil {
+			return "", err
+		}
+		if err := a.remember(ctx, t, feedback); err != nil {
+			return "", err
+		}
+		a.mutex.RLock()
+		messages = a.prepareMessages(t)
+		a.mutex.RUnlock()
+	} else {
+		a.mutex.RLock()
+		messages = append(a.prepareMessages(t), draft, feedback)
+		a.mutex.RUnlock()
+	}
+
+	content, err := a.completeAnswer(ctx, t, messages)
+	if err != nil {
+		return "", fmt.Errorf("error revising answer: %w", err)
+	}
+	return content, nil
+}
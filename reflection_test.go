@@ -0,0 +1,227 @@
+package syndicate
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestReflectionRevisesUntilThreshold verifica que el agente revise el borrador hasta superar el umbral.
+func TestReflectionRevisesUntilThreshold(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse("borrador"),
+		textResponse("versión mejorada"),
+	}}
+	critic := &recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse(`{"score":4,"feedback":"faltan cifras"}`),
+		textResponse(`{"score":9,"feedback":"bien"}`),
+	}}
+	mem := NewSimpleMemory()
+
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("writer"),
+		WithMemory(mem),
+		WithModel("gpt-4o"),
+		WithReflection(
+			WithCritic(critic, "critic-model"),
+			WithCriticRubric("Debe incluir cifras."),
+		),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	result, err := agent.(DetailedAgent).ChatDetailed(context.Background(), WithUserName("user"), WithInput("escribe el informe"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if result.Content != "versión mejorada" {
+		t.Errorf("respuesta inesperada: %s", result.Content)
+	}
+	if len(result.Critiques) != 2 || result.Critiques[0].Passed || !result.Critiques[1].Passed || result.Critiques[0].Draft != "borrador" {
+		t.Errorf("críticas inesperadas: %+v", result.Critiques)
+	}
+	if result.Rounds != 4 {
+		t.Errorf("se esperaban 4 rondas, se obtuvo %d", result.Rounds)
+	}
+
+	criticReq := critic.Requests()[0]
+	if criticReq.Model != "critic-model" || !strings.Contains(criticReq.Messages[0].Content, "Debe incluir cifras.") {
+		t.Errorf("request del crítico inesperado: %+v", criticReq)
+	}
+	if !strings.Contains(criticReq.Messages[1].Content, "escribe el informe") || !strings.Contains(criticReq.Messages[1].Content, "borrador") {
+		t.Errorf("el crítico no recibió la petición y el borrador: %s", criticReq.Messages[1].Content)
+	}
+	revision := client.Requests()[1].Messages
+	if last := revision[len(revision)-1]; !strings.Contains(last.Content, "faltan cifras") {
+		t.Errorf("la revisión no incluyó la crítica: %+v", last)
+	}
+
+	if msgs := mem.Get(); len(msgs) != 2 || msgs[1].Content != "versión mejorada" {
+		t.Errorf("la memoria solo debía guardar la respuesta final: %+v", msgs)
+	}
+}
+
+// TestReflectionMaxRevisions verifica que se acepte el último borrador al agotar las revisiones y que los borradores se guarden si se pide.
+func TestReflectionMaxRevisions(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse("borrador"),
+		textResponse("revisión"),
+	}}
+	critic := &recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse(`{"score":2,"feedback":"mal"}`),
+		textResponse(`{"score":3,"feedback":"sigue mal"}`),
+	}}
+	mem := NewSimpleMemory()
+
+	agent, _ := NewAgent(
+		WithClient(client),
+		WithName("writer"),
+		WithMemory(mem),
+		WithModel("gpt-4o"),
+		WithReflection(WithCritic(critic, ""), WithMaxRevisions(1), WithDraftsInMemory()),
+	)
+
+	result, err := agent.(DetailedAgent).ChatDetailed(context.Background(), WithUserName("user"), WithInput("escribe"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if result.Content != "revisión" || len(result.Critiques) != 2 {
+		t.Errorf("resultado inesperado: %s %+v", result.Content, result.Critiques)
+	}
+	if critic.Requests()[0].Model != "gpt-4o" {
+		t.Error("el crítico debía usar el modelo del agente por defecto")
+	}
+	if msgs := mem.Get(); len(msgs) != 4 || msgs[1].Content != "borrador" || msgs[2].Name != "critic" {
+		t.Errorf("la memoria debía incluir el borrador y la crítica: %+v", msgs)
+	}
+}
+
+// TestReflectionPlanAndExecute verifica que solo se critique la respuesta final y no cada paso del plan.
+func TestReflectionPlanAndExecute(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse(`{"steps":["buscar el precio","calcular el total"]}`),
+		textResponse("el precio es 10"),
+		textResponse("el total es 20"),
+		textResponse("Debes pagar 20"),
+	}}
+	critic := &recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse(`{"score":9,"feedback":"bien"}`),
+	}}
+
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("planner"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithStrategy(PlanAndExecuteStrategy(0)),
+		WithReflection(WithCritic(critic, "critic-model")),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	result, err := agent.(DetailedAgent).ChatDetailed(context.Background(), WithUserName("user"), WithInput("¿Cuánto pago por dos?"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if n := len(critic.Requests()); n != 1 {
+		t.Fatalf("se esperaba 1 llamada al crítico, hubo %d", n)
+	}
+	if len(result.Critiques) != 1 || result.Critiques[0].Draft != "Debes pagar 20" {
+		t.Errorf("críticas inesperadas: %+v", result.Critiques)
+	}
+	if content := critic.Requests()[0].Messages[1].Content; !strings.Contains(content, "¿Cuánto pago por dos?") {
+		t.Errorf("el crítico no recibió la petición del usuario: %s", content)
+	}
+}
+
+// TestReflectionCritiqueRound verifica que la crítica pase por los hooks, los eventos y el uso del turno.
+func TestReflectionCritiqueRound(t *testing.T) {
+	answer := textResponse("borrador")
+	answer.Choices[0].FinishReason = FinishReasonLength
+	answer.Usage = Usage{TotalTokens: 10}
+	verdict := textResponse(`{"score":9,"feedback":"bien"}`)
+	verdict.Usage = Usage{TotalTokens: 4}
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{answer}}
+	critic := &recordingLLMClient{responses: []ChatCompletionResponse{verdict}}
+
+	var models []string
+	events := &eventRecorder{}
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("writer"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithReflection(WithCritic(critic, "critic-model")),
+		WithHooks(Hooks{BeforeRequest: func(ctx context.Context, req *ChatCompletionRequest) error {
+			models = append(models, req.Model)
+			return nil
+		}}),
+		WithEventHandler(events.handle),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	result, err := agent.(DetailedAgent).ChatDetailed(context.Background(), WithUserName("user"), WithInput("escribe"))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if strings.Join(models, ",") != "gpt-4o,critic-model" {
+		t.Errorf("los hooks no vieron la crítica: %v", models)
+	}
+	if n := strings.Count(events.types(), string(EventRequestSent)); n != 2 {
+		t.Errorf("se esperaban 2 eventos request_sent, hubo %d: %s", n, events.types())
+	}
+	if result.Rounds != 2 || result.Usage.TotalTokens != 14 || result.FinishReason != FinishReasonLength {
+		t.Errorf("resultado inesperado: %d rondas, %+v, %s", result.Rounds, result.Usage, result.FinishReason)
+	}
+}
+
+// TestReflectionContentDeltas verifica que la crítica no aparezca entre los fragmentos de contenido.
+func TestReflectionContentDeltas(t *testing.T) {
+	client := &streamingTestClient{recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse("primer borrador"),
+		textResponse("versión final"),
+	}}}
+	critic := &streamingTestClient{recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse(`{"score":3,"feedback":"incompleto"}`),
+		textResponse(`{"score":9,"feedback":"bien"}`),
+	}}}
+	events := &eventRecorder{}
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("writer"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithReflection(WithCritic(critic, "critic-model")),
+		WithEventHandler(events.handle),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("escribe")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	want := []string{"primer", " borrador", "versión", " final"}
+	if got := events.deltas(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("fragmentos inesperados: %q, se esperaba %q", got, want)
+	}
+}
+
+// TestReflectionOptionsValidation verifica la validación de las opciones de reflexión.
+func TestReflectionOptionsValidation(t *testing.T) {
+	for name, option := range map[string]ReflectionOption{
+		"umbral inválido": WithReflectionThreshold(11),
+		"revisiones cero": WithMaxRevisions(0),
+		"rúbrica vacía":   WithCriticRubric(""),
+	} {
+		_, err := NewAgent(WithClient(&recordingLLMClient{}), WithName("a"), WithMemory(NewSimpleMemory()), WithModel("m"), WithReflection(option))
+		if err == nil {
+			t.Errorf("%s: se esperaba un error", name)
+		}
+	}
+}
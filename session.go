@@ -0,0 +1,116 @@
+package syndicate
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MemoryFactory creates the memory backing a conversation session.
+// It is called once per session ID, the first time the session is used, and again when a deleted
+// session is used.
+type MemoryFactory func(sessionID string) (Memory, error)
+
+// session holds the isolated history of one conversation and serializes its chat turns.
+type session struct {
+	memory Memory
+	mutex  sync.Mutex
+	ready  chan struct{} // Closed once memory (or err) is set.
+	err    error         // Why the memory could not be created.
+}
+
+// WithMemoryFactory sets the factory used to create the memory of each conversation session.
+// When no memory is configured with WithMemory, the default session also uses the factory.
+// Without a factory, new sessions start with a NewSimpleMemory.
+//
+// Example:
+//
+//	agent, err := syndicate.NewAgent(
+//		syndicate.WithMemoryFactory(func(sessionID string) (syndicate.Memory, error) {
+//			return NewDatabaseMemory(db, sessionID)
+//		}),
+//		// ... other options
+//	)
+//	response, err := agent.Chat(ctx,
+//		syndicate.WithSessionID("user-42"),
+//		syndicate.WithUserName("Alice"),
+//		syndicate.WithInput("Hello!"),
+//	)
+func WithMemoryFactory(factory MemoryFactory) AgentOption {
+	return func(a *agent) error {
+		if factory == nil {
+			return errors.New("memory factory cannot be nil")
+		}
+		a.memoryFactory = factory
+		return nil
+	}
+}
+
+// SessionAgent is implemented by agents that keep conversation sessions. Sessions are created on
+// first use and kept until they are deleted.
+type SessionAgent interface {
+	Agent
+	// DeleteSession forgets the session with the given ID and reports whether it existed. A turn
+	// already running in the session finishes normally; the next chat with the same ID starts a new
+	// session, with memory from the memory factory. The memory of the session is not cleared.
+	DeleteSession(sessionID string) bool
+}
+
+// DeleteSession forgets the session with the given ID.
+func (a *agent) DeleteSession(sessionID string) bool {
+	a.sessionsMutex.Lock()
+	defer a.sessionsMutex.Unlock()
+	_, ok := a.sessions[sessionID]
+	delete(a.sessions, sessionID)
+	return ok
+}
+
+// session returns the session for the given ID, creating it on first use. The memory of a new
+// session is created without holding the sessions lock; concurrent callers wait for it.
+func (a *agent) session(id string) (*session, error) {
+	a.sessionsMutex.Lock()
+	if sess, ok := a.sessions[id]; ok {
+		a.sessionsMutex.Unlock()
+		<-sess.ready
+		if sess.err != nil {
+			return nil, sess.err
+		}
+		return sess, nil
+	}
+	sess := &session{ready: make(chan struct{})}
+	a.sessions[id] = sess
+	a.sessionsMutex.Unlock()
+
+	sess.memory, sess.err = a.newSessionMemory(id)
+	if sess.err != nil {
+		a.sessionsMutex.Lock()
+		if a.sessions[id] == sess {
+			delete(a.sessions, id)
+		}
+		a.sessionsMutex.Unlock()
+	}
+	close(sess.ready)
+	if sess.err != nil {
+		return nil, sess.err
+	}
+	return sess, nil
+}
+
+// newSessionMemory creates the memory of a new session.
+func (a *agent) newSessionMemory(id string) (Memory, error) {
+	switch {
+	case id == "" && a.memory != nil:
+		return a.memory, nil
+	case a.memoryFactory != nil:
+		memory, err := a.memoryFactory(id)
+		if err != nil {
+			return nil, fmt.Errorf("error creating memory for session %q: %w", id, err)
+		}
+		if memory == nil {
+			return nil, fmt.Errorf("memory factory returned nil memory for session %q", id)
+		}
+		return memory, nil
+	default:
+		return NewSimpleMemory(), nil
+	}
+}
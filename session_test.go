@@ -0,0 +1,201 @@
+package syndicate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// echoLLMClient responde con el contenido del último mensaje recibido; es seguro para uso concurrente.
+type echoLLMClient struct{}
+
+func (echoLLMClient) CreateChatCompletion(ctx context.Context, req ChatCompletionRequest) (ChatCompletionResponse, error) {
+	last := req.Messages[len(req.Messages)-1]
+	return textResponse("echo: " + last.Content), nil
+}
+
+// TestSessionsAreIsolated verifica que cada sesión tenga su propio historial.
+func TestSessionsAreIsolated(t *testing.T) {
+	var mu sync.Mutex
+	memories := make(map[string]Memory)
+
+	agent, err := NewAgent(
+		WithClient(echoLLMClient{}),
+		WithName("sessions"),
+		WithModel("gpt-4o"),
+		WithMemoryFactory(func(sessionID string) (Memory, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if _, exists := memories[sessionID]; exists {
+				return nil, fmt.Errorf("factory llamada dos veces para %s", sessionID)
+			}
+			memories[sessionID] = NewSimpleMemory()
+			return memories[sessionID], nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente sin memoria pero con factory: %v", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		for _, id := range []string{"alice", "bob"} {
+			if _, err := agent.Chat(ctx, WithSessionID(id), WithUserName(id), WithInput("hola de "+id)); err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+		}
+	}
+
+	for _, id := range []string{"alice", "bob"} {
+		msgs := memories[id].Get()
+		if len(msgs) != 4 {
+			t.Fatalf("se esperaban 4 mensajes en la sesión %s, se obtuvieron %d", id, len(msgs))
+		}
+		for _, msg := range msgs {
+			if !strings.Contains(msg.Content, id) {
+				t.Errorf("la sesión %s contiene un mensaje ajeno: %q", id, msg.Content)
+			}
+		}
+	}
+}
+
+// TestSessionsConcurrentChats verifica que los chats concurrentes no mezclen mensajes dentro de una sesión.
+func TestSessionsConcurrentChats(t *testing.T) {
+	agent, _ := NewAgent(
+		WithClient(echoLLMClient{}),
+		WithName("sessions"),
+		WithModel("gpt-4o"),
+		WithMemory(NewSimpleMemory()),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("s%d", i%4)
+			input := fmt.Sprintf("mensaje %d", i)
+			result, err := agent.Chat(context.Background(), WithSessionID(id), WithUserName("user"), WithInput(input))
+			if err != nil {
+				t.Errorf("error inesperado: %v", err)
+				return
+			}
+			if result != "echo: "+input {
+				t.Errorf("respuesta mezclada: se esperaba eco de %q, se obtuvo %q", input, result)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// TestSessionsDefaultMemory verifica que la sesión por defecto use la memoria configurada.
+func TestSessionsDefaultMemory(t *testing.T) {
+	mem := NewSimpleMemory()
+	agent, _ := NewAgent(
+		WithClient(echoLLMClient{}),
+		WithName("sessions"),
+		WithModel("gpt-4o"),
+		WithMemory(mem),
+	)
+
+	ctx := context.Background()
+	agent.Chat(ctx, WithUserName("user"), WithInput("por defecto"))
+	agent.Chat(ctx, WithSessionID("otra"), WithUserName("user"), WithInput("aislado"))
+
+	msgs := mem.Get()
+	if len(msgs) != 2 || msgs[0].Content != "por defecto" {
+		t.Errorf("la memoria por defecto no contiene lo esperado: %+v", msgs)
+	}
+}
+
+// TestSessionsFactoryError verifica que los errores de la factory se propaguen.
+func TestSessionsFactoryError(t *testing.T) {
+	agent, _ := NewAgent(
+		WithClient(echoLLMClient{}),
+		WithName("sessions"),
+		WithModel("gpt-4o"),
+		WithMemoryFactory(func(sessionID string) (Memory, error) {
+			return nil, errors.New("db caída")
+		}),
+	)
+
+	_, err := agent.Chat(context.Background(), WithSessionID("x"), WithUserName("user"), WithInput("hola"))
+	if err == nil || !strings.Contains(err.Error(), "db caída") {
+		t.Errorf("se esperaba el error de la factory, se obtuvo: %v", err)
+	}
+
+	if _, err := NewAgent(WithMemoryFactory(nil)); err == nil || !strings.Contains(err.Error(), "memory factory cannot be nil") {
+		t.Errorf("se esperaba error por factory nil, se obtuvo: %v", err)
+	}
+}
+
+// TestSessionsDelete verifica que una sesión borrada empiece de nuevo con memoria de la factory.
+func TestSessionsDelete(t *testing.T) {
+	var created []string
+	agent, err := NewAgent(
+		WithClient(echoLLMClient{}),
+		WithName("sessions"),
+		WithModel("gpt-4o"),
+		WithMemoryFactory(func(sessionID string) (Memory, error) {
+			created = append(created, sessionID)
+			return NewSimpleMemory(), nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+	sessions := agent.(SessionAgent)
+
+	ctx := context.Background()
+	agent.Chat(ctx, WithSessionID("a"), WithUserName("user"), WithInput("hola"))
+	if !sessions.DeleteSession("a") {
+		t.Error("la sesión debía existir")
+	}
+	if sessions.DeleteSession("a") {
+		t.Error("la sesión ya había sido borrada")
+	}
+	agent.Chat(ctx, WithSessionID("a"), WithUserName("user"), WithInput("de nuevo"))
+	if len(created) != 2 {
+		t.Errorf("se esperaban 2 memorias creadas, hubo %v", created)
+	}
+}
+
+// TestSessionsFactoryDoesNotBlockOtherSessions verifica que una factory lenta no bloquee otras sesiones.
+func TestSessionsFactoryDoesNotBlockOtherSessions(t *testing.T) {
+	release := make(chan struct{})
+	agent, err := NewAgent(
+		WithClient(echoLLMClient{}),
+		WithName("sessions"),
+		WithModel("gpt-4o"),
+		WithMemoryFactory(func(sessionID string) (Memory, error) {
+			if sessionID == "lenta" {
+				<-release
+			}
+			return NewSimpleMemory(), nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	ctx := context.Background()
+	slow := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := agent.Chat(ctx, WithSessionID("lenta"), WithUserName("user"), WithInput("hola"))
+			slow <- err
+		}()
+	}
+	if _, err := agent.Chat(ctx, WithSessionID("rapida"), WithUserName("user"), WithInput("hola")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	close(release)
+	for i := 0; i < 2; i++ {
+		if err := <-slow; err != nil {
+			t.Errorf("error inesperado en la sesión lenta: %v", err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Strategy decides how an agent reasons and uses its tools to answer a chat turn.
+// The available strategies are NativeToolStrategy (the default), ReActStrategy and PlanAndExecuteStrategy.
+type Strategy interface {
+	run(ctx context.Context, a *agent, t *turn, messages []Message) error
+}
+
+// nativeStrategy runs the provider's native tool-call loop.
+type nativeStrategy struct{}
+
+func (nativeStrategy) run(ctx context.Context, a *agent, t *turn, messages []Message) error {
+	return a.processWithTools(ctx, t, messages)
+}
+
+// NativeToolStrategy returns the default strategy, which sends tool definitions to the provider
+// and executes the tool calls it requests until the model produces a final answer.
+func NativeToolStrategy() Strategy {
+	return nativeStrategy{}
+}
+
+// WithStrategy sets how the agent reasons and uses tools within each chat turn.
+func WithStrategy(strategy Strategy) AgentOption {
+	return func(a *agent) error {
+		if strategy == nil {
+			return errors.New("strategy cannot be nil")
+		}
+		a.strategy = strategy
+		return nil
+	}
+}
+
+// withInstructions returns messages with instructions appended to the system prompt,
+// adding a system message when the turn has no system prompt.
+func (a *agent) withInstructions(t *turn, messages []Message, instructions string) []Message {
+	a.mutex.RLock()
+	role := getSystemRole(a.model)
+	a.mutex.RUnlock()
+
+	if t.systemPrompt == "" {
+		return append([]Message{{Role: role, Content: instructions}}, messages...)
+	}
+	result := make([]Message, len(messages))
+	copy(result, messages)
+	result[0].Content = t.systemPrompt + "\n\n" + instructions
+	return result
+}
+
+// describeTools renders tool definitions for strategies that explain the tools in the prompt.
+func describeTools(tools []ToolDefinition) string {
+	if len(tools) == 0 {
+		return "(no tools available)"
+	}
+	var sb strings.Builder
+	for _, tool := range tools {
+		fmt.Fprintf(&sb, "- %s: %s\n", tool.Name, tool.Description)
+		if tool.Parameters != nil {
+			if params, err := json.Marshal(tool.Parameters); err == nil {
+				fmt.Fprintf(&sb, "  Arguments schema: %s\n", params)
+			}
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
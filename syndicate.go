@@ -2,8 +2,11 @@ package syndicate
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"sync"
+	"time"
 )
 
 // Syndicate defines the interface for managing multiple agents and pipelines.
@@ -16,12 +19,28 @@ type Syndicate interface {
 	GetPipeline() []string
 }
 
+// DetailedSyndicate is a Syndicate that reports the detailed result of agent executions.
+// Syndicates created with NewSyndicate implement it.
+type DetailedSyndicate interface {
+	Syndicate
+	ExecuteAgentDetailed(ctx context.Context, agentName string, options ...ExecuteAgentOption) (*ChatResult, error)
+}
+
 // syndicate is the private implementation of the Syndicate interface.
 type syndicate struct {
-	agents        map[string]Agent // Registered agents identified by their names.
-	globalHistory Memory           // Global conversation history shared across agents.
-	pipeline      []string         // Ordered pipeline of agent names for sequential processing.
-	mutex         sync.RWMutex     // RWMutex to ensure thread-safe access to the syndicate.
+	agents        map[string]Agent  // Registered agents identified by their names.
+	globalHistory Memory            // Global conversation history shared across agents.
+	pipeline      []string          // Ordered pipeline of agent names for sequential processing.
+	mutex         sync.RWMutex      // RWMutex to ensure thread-safe access to the syndicate.
+	tracer        Tracer            // Tracer used to emit spans for pipelines and agent executions.
+	logger        *slog.Logger      // Structured logger; discards records by default.
+	handoffs      map[string][]Tool // Handoff tools offered to each agent, keyed by agent name.
+	maxHandoffs   int               // Maximum handoffs followed by a single agent execution.
+
+	inputGuardrails  []Guardrail // Input guardrails applied to every agent execution.
+	outputGuardrails []Guardrail // Output guardrails applied to every agent execution.
+
+	checkpoints CheckpointStore // Saves the progress of pipeline runs; nil disables checkpoints.
 }
 
 // SyndicateOption defines a function that configures a syndicate.
@@ -94,6 +113,10 @@ func NewSyndicate(options ...SyndicateOption) (Syndicate, error) {
 		agents:        make(map[string]Agent),
 		globalHistory: NewSimpleMemory(),
 		pipeline:      []string{},
+		handoffs:      make(map[string][]Tool),
+		maxHandoffs:   defaultMaxHandoffs,
+		tracer:        noopTracer{},
+		logger:        discardLogger(),
 	}
 
 	// Apply all options
@@ -116,6 +139,9 @@ type executeAgentRequest struct {
 	imageURLs          []string
 	additionalMessages [][]Message
 	useGlobalHistory   bool
+	eventHandlers      []EventHandler
+	runID              string // Checkpoint run ID of the first agent; handoff targets get derived IDs.
+	resume             bool   // Whether to resume the agents' checkpoints instead of starting over.
 }
 
 // WithExecuteUserName sets the user name for agent execution.
@@ -155,6 +181,43 @@ func WithGlobalHistoryContext() ExecuteAgentOption {
 
 // ExecuteAgent runs a specific agent with the provided options.
 func (s *syndicate) ExecuteAgent(ctx context.Context, agentName string, options ...ExecuteAgentOption) (string, error) {
+	result, err := s.ExecuteAgentDetailed(ctx, agentName, options...)
+	if err != nil {
+		return "", err
+	}
+	return result.Content, nil
+}
+
+// ExecuteAgentDetailed runs a specific agent and returns the detailed result of its chat turn.
+// When the agent hands the conversation off (see WithHandoffs), the target agent is executed in turn;
+// the result then names the agent that produced the answer, lists the handoffs followed and
+// aggregates the usage of every agent involved.
+// Agents that do not implement DetailedAgent only report the AgentName, Content and Duration.
+func (s *syndicate) ExecuteAgentDetailed(ctx context.Context, agentName string, options ...ExecuteAgentOption) (*ChatResult, error) {
+	ctx, span := s.tracer.Start(ctx, SpanExecuteAgent, Attr(AttrAgentName, agentName))
+	defer span.End()
+
+	start := time.Now()
+	result, err := s.executeAgent(ctx, agentName, options...)
+	if err != nil {
+		span.RecordError(err)
+		s.logger.ErrorContext(ctx, "agent execution failed",
+			slog.String("agent", agentName),
+			slog.Duration("latency", time.Since(start)),
+			slog.Any("error", err),
+		)
+		return nil, err
+	}
+	s.logger.DebugContext(ctx, "agent executed",
+		slog.String("agent", agentName),
+		slog.Duration("latency", time.Since(start)),
+		slog.Int("total_tokens", result.Usage.TotalTokens),
+	)
+	return result, nil
+}
+
+// executeAgent implements ExecuteAgentDetailed inside the span started by the caller.
+func (s *syndicate) executeAgent(ctx context.Context, agentName string, options ...ExecuteAgentOption) (*ChatResult, error) {
 	// Apply default values
 	req := &executeAgentRequest{
 		useGlobalHistory: true, // Default to using global history
@@ -167,16 +230,60 @@ func (s *syndicate) ExecuteAgent(ctx context.Context, agentName string, options
 
 	// Validate required fields
 	if req.userName == "" {
-		return "", fmt.Errorf("user name is required")
+		return nil, fmt.Errorf("user name is required")
 	}
 	if req.input == "" {
-		return "", fmt.Errorf("input is required")
+		return nil, fmt.Errorf("input is required")
 	}
 
+	start := time.Now()
+	var handoffs []Handoff
+	var handoffMessages []Message
+	var usage Usage
+	current := agentName
+	for {
+		runID := req.runID
+		if runID != "" && len(handoffs) > 0 {
+			runID = fmt.Sprintf("%s/handoff-%d", req.runID, len(handoffs))
+		}
+		result, err := s.chatAgent(ctx, current, req, handoffMessages, runID)
+		if err != nil {
+			return nil, err
+		}
+		usage = usage.Add(result.Usage)
+
+		if result.Handoff == nil {
+			if len(handoffs) > 0 {
+				result.Usage = usage
+				result.Handoffs = handoffs
+				result.Duration = time.Since(start)
+			}
+			s.recordExecution(req, handoffs, result)
+			return result, nil
+		}
+
+		handoff := *result.Handoff
+		if len(handoffs) >= s.maxHandoffs {
+			return nil, fmt.Errorf("agent %s handed off to %s: %w", handoff.From, handoff.Target, ErrMaxHandoffs)
+		}
+		s.logger.InfoContext(ctx, "conversation handed off",
+			slog.String("from", handoff.From),
+			slog.String("to", handoff.Target),
+		)
+		handoffs = append(handoffs, handoff)
+		handoffMessages = append(handoffMessages, handoffMessage(handoff))
+		current = handoff.Target
+	}
+}
+
+// chatAgent runs a single chat turn of the named agent for an execution request,
+// offering it the handoff tools configured for it. A non-empty runID checkpoints the turn
+// in the syndicate's checkpoint store, and resumes it when the request asks to.
+func (s *syndicate) chatAgent(ctx context.Context, agentName string, req *executeAgentRequest, handoffMessages []Message, runID string) (*ChatResult, error) {
 	// Retrieve the agent by its name
 	agent, exists := s.FindAgent(agentName)
 	if !exists {
-		return "", fmt.Errorf("agent not found: %s", agentName)
+		return nil, fmt.Errorf("agent not found: %s", agentName)
 	}
 
 	// Prepare chat options for the agent
@@ -206,30 +313,89 @@ func (s *syndicate) ExecuteAgent(ctx context.Context, agentName string, options
 		chatOptions = append(chatOptions, WithAdditionalMessages(msgs))
 	}
 
+	// Let the agent see why the conversation was transferred to it
+	if len(handoffMessages) > 0 {
+		chatOptions = append(chatOptions, WithAdditionalMessages(handoffMessages))
+	}
+
+	// Apply the syndicate-wide guardrails on top of the agent's own
+	if len(s.inputGuardrails) > 0 {
+		chatOptions = append(chatOptions, WithChatInputGuardrails(s.inputGuardrails...))
+	}
+	if len(s.outputGuardrails) > 0 {
+		chatOptions = append(chatOptions, WithChatOutputGuardrails(s.outputGuardrails...))
+	}
+
+	for _, handler := range req.eventHandlers {
+		chatOptions = append(chatOptions, WithChatEventHandler(handler))
+	}
+
+	// Offer the handoff tools configured for this agent
+	s.mutex.RLock()
+	handoffTools := s.handoffs[agentName]
+	s.mutex.RUnlock()
+	if len(handoffTools) > 0 {
+		chatOptions = append(chatOptions, WithExtraTools(handoffTools...))
+	}
+
+	if runID != "" && s.checkpoints != nil {
+		chatOptions = append(chatOptions, WithRunID(runID), WithChatCheckpointStore(s.checkpoints))
+		if resumable, ok := agent.(ResumableAgent); ok && req.resume {
+			if _, err := s.checkpoints.Load(ctx, runID); err == nil {
+				return resumable.Resume(ctx, runID, chatOptions...)
+			}
+		}
+	}
+
 	// Execute the agent
-	response, err := agent.Chat(ctx, chatOptions...)
+	result, err := chatDetailed(ctx, agent, chatOptions...)
 	if err != nil {
-		return "", fmt.Errorf("error executing agent %s: %w", agentName, err)
+		return nil, fmt.Errorf("error executing agent %s: %w", agentName, err)
 	}
+	return result, nil
+}
 
-	// Update global history
+// recordExecution adds a completed execution to the global history:
+// the user's input, any handoffs and the answer of the agent that produced it.
+func (s *syndicate) recordExecution(req *executeAgentRequest, handoffs []Handoff, result *ChatResult) {
 	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
 	s.globalHistory.Add(Message{
 		Role:    RoleUser,
 		Content: req.input,
 		Name:    req.userName,
 	})
 
+	for _, handoff := range handoffs {
+		s.globalHistory.Add(handoffMessage(handoff))
+	}
+
 	// Prefix the agent's response with its name for clarity in global history
-	prefixedResponse := fmt.Sprintf("[%s]: %s", agentName, response)
+	prefixedResponse := fmt.Sprintf("[%s]: %s", result.AgentName, result.Content)
 	s.globalHistory.Add(Message{
 		Role:    RoleAssistant,
 		Content: prefixedResponse,
-		Name:    agentName,
+		Name:    result.AgentName,
 	})
-	s.mutex.Unlock()
+}
 
-	return response, nil
+// chatDetailed runs a chat turn on any Agent, falling back to Chat for agents that do not implement DetailedAgent.
+func chatDetailed(ctx context.Context, agent Agent, options ...ChatOption) (*ChatResult, error) {
+	if detailed, ok := agent.(DetailedAgent); ok {
+		return detailed.ChatDetailed(ctx, options...)
+	}
+
+	start := time.Now()
+	response, err := agent.Chat(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &ChatResult{
+		AgentName: agent.GetName(),
+		Content:   response,
+		Duration:  time.Since(start),
+	}, nil
 }
 
 // PipelineOption defines options for pipeline execution.
@@ -237,9 +403,11 @@ type PipelineOption func(*pipelineRequest)
 
 // pipelineRequest holds parameters for pipeline execution.
 type pipelineRequest struct {
-	userName  string
-	input     string
-	imageURLs []string
+	userName      string
+	input         string
+	imageURLs     []string
+	eventHandlers []EventHandler
+	runID         string
 }
 
 // WithPipelineUserName sets the user name for pipeline execution.
@@ -263,8 +431,75 @@ func WithPipelineImages(imageURLs ...string) PipelineOption {
 	}
 }
 
+// WithPipelineRunID sets the run ID under which the pipeline is checkpointed (see WithSyndicateCheckpointStore).
+// Without it, checkpointed pipelines get a random run ID, reported in the logs.
+func WithPipelineRunID(runID string) PipelineOption {
+	return func(r *pipelineRequest) {
+		r.runID = runID
+	}
+}
+
+// WithSyndicateCheckpointStore saves the progress of every pipeline run to store, so an interrupted
+// run can be continued with ResumePipeline. The pipeline is checkpointed after each agent, and each
+// agent's chat turn is checkpointed in the same store under "<run ID>/step-<n>", so the interrupted
+// agent resumes from its last completed tool call when it implements ResumableAgent.
+func WithSyndicateCheckpointStore(store CheckpointStore) SyndicateOption {
+	return func(s *syndicate) error {
+		if store == nil {
+			return errors.New("checkpoint store cannot be nil")
+		}
+		s.checkpoints = store
+		return nil
+	}
+}
+
+// withExecuteRun checkpoints the agent execution under runID, resuming its checkpoints when resume is set.
+func withExecuteRun(runID string, resume bool) ExecuteAgentOption {
+	return func(r *executeAgentRequest) {
+		r.runID = runID
+		r.resume = resume
+	}
+}
+
 // ExecutePipeline runs a sequence of agents as defined in the syndicate's pipeline.
 func (s *syndicate) ExecutePipeline(ctx context.Context, options ...PipelineOption) (string, error) {
+	return s.trackPipeline(ctx, func(ctx context.Context) (string, error) {
+		return s.executePipeline(ctx, options...)
+	})
+}
+
+// ResumePipeline continues a checkpointed pipeline run; see ResumableSyndicate.
+func (s *syndicate) ResumePipeline(ctx context.Context, runID string, options ...PipelineOption) (string, error) {
+	return s.trackPipeline(ctx, func(ctx context.Context) (string, error) {
+		return s.resumePipeline(ctx, runID, options...)
+	})
+}
+
+// trackPipeline runs a pipeline inside its span, reporting its outcome to the logs.
+func (s *syndicate) trackPipeline(ctx context.Context, run func(context.Context) (string, error)) (string, error) {
+	ctx, span := s.tracer.Start(ctx, SpanPipeline, Attr(AttrPipelineLength, len(s.pipeline)))
+	defer span.End()
+
+	start := time.Now()
+	response, err := run(ctx)
+	if err != nil {
+		span.RecordError(err)
+		s.logger.ErrorContext(ctx, "pipeline failed",
+			slog.Any("pipeline", s.pipeline),
+			slog.Duration("latency", time.Since(start)),
+			slog.Any("error", err),
+		)
+		return "", err
+	}
+	s.logger.InfoContext(ctx, "pipeline completed",
+		slog.Any("pipeline", s.pipeline),
+		slog.Duration("latency", time.Since(start)),
+	)
+	return response, nil
+}
+
+// executePipeline implements ExecutePipeline inside the span started by the caller.
+func (s *syndicate) executePipeline(ctx context.Context, options ...PipelineOption) (string, error) {
 	if len(s.pipeline) == 0 {
 		return "", fmt.Errorf("no pipeline defined in syndicate")
 	}
@@ -285,31 +520,124 @@ func (s *syndicate) ExecutePipeline(ctx context.Context, options ...PipelineOpti
 		return "", fmt.Errorf("input is required")
 	}
 
-	currentInput := req.input
-	var currentImages []string = req.imageURLs // Images only for first agent
+	state := &PipelineState{
+		Agents:    s.GetPipeline(),
+		UserName:  req.userName,
+		Input:     req.input,
+		ImageURLs: req.imageURLs,
+	}
+
+	var cp *Checkpoint
+	if s.checkpoints != nil {
+		runID := req.runID
+		if runID == "" {
+			runID = newRunID()
+		}
+		cp = &Checkpoint{RunID: runID, Pipeline: state}
+		s.logger.InfoContext(ctx, "pipeline run started", slog.String("run_id", runID))
+		if err := s.savePipeline(ctx, cp, CheckpointStageRunning); err != nil {
+			return "", err
+		}
+	}
+
+	return s.runPipeline(ctx, state, cp, req.eventHandlers, false)
+}
+
+// resumePipeline implements ResumePipeline inside the span started by the caller.
+func (s *syndicate) resumePipeline(ctx context.Context, runID string, options ...PipelineOption) (string, error) {
+	if s.checkpoints == nil {
+		return "", errors.New("no checkpoint store configured")
+	}
+	req := &pipelineRequest{}
+	for _, opt := range options {
+		opt(req)
+	}
+
+	cp, err := s.checkpoints.Load(ctx, runID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load checkpoint %s: %w", runID, err)
+	}
+	if cp.Pipeline == nil {
+		return "", fmt.Errorf("checkpoint %s is not a pipeline run", runID)
+	}
+	if cp.Stage == CheckpointStageCompleted {
+		return cp.Pipeline.Input, nil
+	}
+
+	s.logger.InfoContext(ctx, "resuming pipeline",
+		slog.String("run_id", runID),
+		slog.Int("step", cp.Pipeline.Step),
+	)
+	return s.runPipeline(ctx, cp.Pipeline, cp, req.eventHandlers, true)
+}
 
-	// Iterate over each agent in the defined pipeline
-	for i, agentName := range s.pipeline {
+// runPipeline runs the remaining agents of a pipeline, feeding each one the answer of the previous one.
+// When cp is not nil, the progress is saved after each agent; resume resumes the first agent's checkpoint.
+func (s *syndicate) runPipeline(ctx context.Context, state *PipelineState, cp *Checkpoint, handlers []EventHandler, resume bool) (string, error) {
+	// Iterate over each remaining agent in the pipeline
+	for state.Step < len(state.Agents) {
+		i := state.Step
+		agentName := state.Agents[i]
 		executeOptions := []ExecuteAgentOption{
-			WithExecuteUserName(req.userName),
-			WithExecuteInput(currentInput),
+			WithExecuteUserName(state.UserName),
+			WithExecuteInput(state.Input),
 			WithGlobalHistoryContext(),
 		}
 
+		for _, handler := range handlers {
+			executeOptions = append(executeOptions, WithExecuteEventHandler(handler))
+		}
+
 		// Only add images to the first agent in the pipeline
-		if i == 0 && len(currentImages) > 0 {
-			executeOptions = append(executeOptions, WithExecuteImages(currentImages...))
+		if i == 0 && len(state.ImageURLs) > 0 {
+			executeOptions = append(executeOptions, WithExecuteImages(state.ImageURLs...))
 		}
 
+		if cp != nil {
+			executeOptions = append(executeOptions, withExecuteRun(fmt.Sprintf("%s/step-%d", cp.RunID, i), resume))
+		}
+		resume = false
+
 		resp, err := s.ExecuteAgent(ctx, agentName, executeOptions...)
 		if err != nil {
-			return "", fmt.Errorf("error in agent %s: %w", agentName, err)
+			err = fmt.Errorf("error in agent %s: %w", agentName, err)
+			if cp != nil {
+				cp.Error = err.Error()
+				if saveErr := s.checkpoints.Save(ctx, cp); saveErr != nil {
+					s.logger.WarnContext(ctx, "failed to save checkpoint",
+						slog.String("run_id", cp.RunID),
+						slog.Any("error", saveErr),
+					)
+				}
+			}
+			return "", err
+		}
+
+		state.Step++
+		state.Input = resp
+		if cp != nil {
+			stage := CheckpointStageRunning
+			if state.Step == len(state.Agents) {
+				stage = CheckpointStageCompleted
+			}
+			if err := s.savePipeline(ctx, cp, stage); err != nil {
+				return "", err
+			}
 		}
-		currentInput = resp
-		currentImages = nil // Clear images after first agent
 	}
 
-	return currentInput, nil
+	return state.Input, nil
+}
+
+// savePipeline saves a pipeline checkpoint at the given stage.
+func (s *syndicate) savePipeline(ctx context.Context, cp *Checkpoint, stage string) error {
+	cp.Stage = stage
+	cp.Error = ""
+	cp.UpdatedAt = time.Now()
+	if err := s.checkpoints.Save(ctx, cp); err != nil {
+		return fmt.Errorf("failed to save checkpoint %s: %w", cp.RunID, err)
+	}
+	return nil
 }
 
 // FindAgent retrieves a registered agent by its name in a thread-safe manner.
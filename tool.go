@@ -1,17 +1,48 @@
 package syndicate
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 )
 
+// ContextTool is a Tool that receives the context of the chat turn executing it.
+// Agents call ExecuteContext instead of Execute for tools implementing it, which lets the tool
+// honour cancellation, nest tracing spans and report usage with ReportToolUsage.
+type ContextTool interface {
+	Tool
+	ExecuteContext(ctx context.Context, args json.RawMessage) (interface{}, error)
+}
+
+// toolCallContextKey is the context key under which agents expose the executing turn to tools.
+type toolCallContextKey struct{}
+
+// toolCallContext describes the chat turn executing a tool call.
+type toolCallContext struct {
+	agentName string
+	turn      *turn
+}
+
+// ReportToolUsage adds token usage consumed by a tool (for example, by a nested LLM call)
+// to the usage of the chat turn executing it. It does nothing when ctx does not come from a tool call.
+func ReportToolUsage(ctx context.Context, usage Usage) {
+	tc, ok := ctx.Value(toolCallContextKey{}).(*toolCallContext)
+	if !ok {
+		return
+	}
+	tc.turn.mutex.Lock()
+	defer tc.turn.mutex.Unlock()
+	tc.turn.result.Usage = tc.turn.result.Usage.Add(usage)
+}
+
 // ToolConfig holds the configuration for creating custom tool implementations
 type ToolConfig struct {
 	Name        string
 	Description string
 	Schema      any
 	ExecuteFunc func(args json.RawMessage) (interface{}, error)
+	Validation  ArgumentValidation // Whether agents validate the arguments; follows the agent by default.
 }
 
 // ToolOption defines a function that configures a Tool implementation
@@ -61,12 +92,25 @@ func WithToolExecuteHandler(executeFunc func(args json.RawMessage) (interface{},
 	}
 }
 
+// WithToolArgumentValidation sets whether agents validate the tool's arguments against its schema
+// before executing it, overriding the agent's WithArgumentValidation setting.
+func WithToolArgumentValidation(enabled bool) ToolOption {
+	return func(config *ToolConfig) error {
+		config.Validation = ArgumentValidationDisabled
+		if enabled {
+			config.Validation = ArgumentValidationEnabled
+		}
+		return nil
+	}
+}
+
 // customTool implements Tool using provided functions
 type customTool struct {
 	name        string
 	description string
 	schema      json.RawMessage
 	executeFunc func(args json.RawMessage) (interface{}, error)
+	validation  ArgumentValidation
 }
 
 func (t *customTool) GetDefinition() ToolDefinition {
@@ -81,6 +125,10 @@ func (t *customTool) Execute(args json.RawMessage) (interface{}, error) {
 	return t.executeFunc(args)
 }
 
+func (t *customTool) ArgumentValidation() ArgumentValidation {
+	return t.validation
+}
+
 // NewTool creates a custom Tool implementation using functional options.
 // Returns an error if required options are not provided.
 //
@@ -132,5 +180,6 @@ func NewTool(options ...ToolOption) (Tool, error) {
 		description: config.Description,
 		schema:      schema,
 		executeFunc: config.ExecuteFunc,
+		validation:  config.Validation,
 	}, nil
 }
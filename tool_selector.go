@@ -0,0 +1,176 @@
+package syndicate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultMaxTools is how many tools a similarity selector offers when no limit is set.
+const defaultMaxTools = 8
+
+// ToolSelector chooses, from the tools allowed in a chat turn, the ones offered to the model for
+// the user's input. Tools passed with WithExtraTools are always offered.
+type ToolSelector interface {
+	SelectTools(ctx context.Context, input string, tools []ToolDefinition) ([]ToolDefinition, error)
+}
+
+// ToolSelectorFunc adapts a function to the ToolSelector interface.
+type ToolSelectorFunc func(ctx context.Context, input string, tools []ToolDefinition) ([]ToolDefinition, error)
+
+// SelectTools calls f.
+func (f ToolSelectorFunc) SelectTools(ctx context.Context, input string, tools []ToolDefinition) ([]ToolDefinition, error) {
+	return f(ctx, input, tools)
+}
+
+// WithToolSelector sets how the tools offered to the model are chosen on each chat turn,
+// such as a selector created with NewSimilarityToolSelector.
+func WithToolSelector(selector ToolSelector) AgentOption {
+	return func(a *agent) error {
+		if selector == nil {
+			return errors.New("tool selector cannot be nil")
+		}
+		a.toolSelector = selector
+		return nil
+	}
+}
+
+// WithEnabledTools offers only the given tools for this chat request. A name selects the tool
+// with that name or, for a toolkit name, every tool of the toolkit.
+func WithEnabledTools(names ...string) ChatOption {
+	return func(r *chatRequest) {
+		r.enabledTools = append(r.enabledTools, names...)
+	}
+}
+
+// WithDisabledTools withholds the given tools for this chat request, matched as in WithEnabledTools.
+func WithDisabledTools(names ...string) ChatOption {
+	return func(r *chatRequest) {
+		r.disabledTools = append(r.disabledTools, names...)
+	}
+}
+
+// toolMatches reports whether the tool called name is pattern or belongs to the toolkit pattern.
+func toolMatches(name, pattern string) bool {
+	return name == pattern || strings.HasPrefix(name, pattern+ToolNamespaceSeparator)
+}
+
+// toolAllowed reports whether a tool passes the enabled and disabled lists of a chat request.
+func toolAllowed(name string, req *chatRequest) bool {
+	matchesAny := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if toolMatches(name, pattern) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(req.enabledTools) > 0 && !matchesAny(req.enabledTools) {
+		return false
+	}
+	return !matchesAny(req.disabledTools)
+}
+
+// similarityToolSelector implements NewSimilarityToolSelector.
+type similarityToolSelector struct {
+	embed    EmbeddingFunc
+	maxTools int
+	always   []string
+	mutex    sync.Mutex
+	vectors  map[string][]float32 // Embeddings of the tool descriptions, by description text.
+}
+
+// NewSimilarityToolSelector returns a selector that offers the maxTools tools whose name and
+// description are most similar to the user's input, by cosine similarity of their embeddings;
+// zero offers 8. The tools matching always (names or toolkit names) are offered in addition, for
+// tools that must never be left out.
+//
+// Tool descriptions are embedded on first use and cached; the user's input is embedded on every turn.
+func NewSimilarityToolSelector(embed EmbeddingFunc, maxTools int, always ...string) (ToolSelector, error) {
+	if embed == nil {
+		return nil, errors.New("embedding function cannot be nil")
+	}
+	if maxTools < 0 {
+		return nil, errors.New("maximum number of tools cannot be negative")
+	}
+	if maxTools == 0 {
+		maxTools = defaultMaxTools
+	}
+	return &similarityToolSelector{
+		embed:    embed,
+		maxTools: maxTools,
+		always:   append([]string(nil), always...),
+		vectors:  make(map[string][]float32),
+	}, nil
+}
+
+func (s *similarityToolSelector) SelectTools(ctx context.Context, input string, tools []ToolDefinition) ([]ToolDefinition, error) {
+	var selected, candidates []ToolDefinition
+	for _, tool := range tools {
+		if s.pinned(tool.Name) {
+			selected = append(selected, tool)
+		} else {
+			candidates = append(candidates, tool)
+		}
+	}
+	if len(candidates) <= s.maxTools {
+		return tools, nil
+	}
+
+	query, err := s.embed(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed input: %w", err)
+	}
+	scores := make([]float64, len(candidates))
+	for i, tool := range candidates {
+		vector, err := s.toolVector(ctx, tool)
+		if err != nil {
+			return nil, err
+		}
+		scores[i] = cosineSimilarity(query, vector)
+	}
+
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return scores[order[i]] > scores[order[j]] })
+	for _, i := range order[:s.maxTools] {
+		selected = append(selected, candidates[i])
+	}
+	return selected, nil
+}
+
+// pinned reports whether the tool called name is always offered.
+func (s *similarityToolSelector) pinned(name string) bool {
+	for _, pattern := range s.always {
+		if toolMatches(name, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// toolVector returns the embedding of a tool's name and description, computing it on first use.
+func (s *similarityToolSelector) toolVector(ctx context.Context, tool ToolDefinition) ([]float32, error) {
+	text := tool.Name + ": " + tool.Description
+
+	s.mutex.Lock()
+	vector, ok := s.vectors[text]
+	s.mutex.Unlock()
+	if ok {
+		return vector, nil
+	}
+
+	vector, err := s.embed(ctx, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed tool %s: %w", tool.Name, err)
+	}
+	s.mutex.Lock()
+	s.vectors[text] = vector
+	s.mutex.Unlock()
+	return vector, nil
+}
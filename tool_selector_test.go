@@ -0,0 +1,106 @@
+package syndicate
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// TestSimilarityToolSelector verifica la selección por similitud, las herramientas fijas y la caché.
+func TestSimilarityToolSelector(t *testing.T) {
+	var calls atomic.Int32
+	selector, err := NewSimilarityToolSelector(keywordEmbedding(&calls), 1, "crm")
+	if err != nil {
+		t.Fatalf("error creando selector: %v", err)
+	}
+	tools := []ToolDefinition{
+		{Name: "billing__search", Description: "Busca una factura"},
+		{Name: "billing__refund", Description: "Devuelve un pago"},
+		{Name: "weather", Description: "Informa el clima y la lluvia"},
+		{Name: "crm__echo", Description: "Repite"},
+	}
+
+	names := func(defs []ToolDefinition) string {
+		var out []string
+		for _, def := range defs {
+			out = append(out, def.Name)
+		}
+		sort.Strings(out)
+		return strings.Join(out, ",")
+	}
+	selected, err := selector.SelectTools(context.Background(), "¿va a haber lluvia?", tools)
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if names(selected) != "crm__echo,weather" {
+		t.Errorf("selección inesperada: %s", names(selected))
+	}
+	selected, _ = selector.SelectTools(context.Background(), "quiero mi factura", tools)
+	if names(selected) != "billing__search,crm__echo" {
+		t.Errorf("selección inesperada: %s", names(selected))
+	}
+	if calls.Load() != 5 {
+		t.Errorf("las descripciones debían embeberse una sola vez, llamadas: %d", calls.Load())
+	}
+
+	if _, err := NewSimilarityToolSelector(nil, 1); err == nil {
+		t.Error("se esperaba error por función de embeddings nula")
+	}
+	if _, err := NewSimilarityToolSelector(keywordEmbedding(&calls), -1); err == nil {
+		t.Error("se esperaba error por límite negativo")
+	}
+}
+
+// TestToolSelectorInAgent verifica que el selector reciba solo las herramientas permitidas y que las extra se ofrezcan siempre.
+func TestToolSelectorInAgent(t *testing.T) {
+	var offered []string
+	selector := ToolSelectorFunc(func(ctx context.Context, input string, tools []ToolDefinition) ([]ToolDefinition, error) {
+		for _, tool := range tools {
+			offered = append(offered, tool.Name)
+		}
+		if input == "falla" {
+			return nil, errors.New("sin embeddings")
+		}
+		return tools[:1], nil
+	})
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{textResponse("ok")}}
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("Agente"),
+		WithMemory(NewSimpleMemory()),
+		WithModel(openai.GPT4),
+		WithToolkits(newTestRegistry(t)),
+		WithToolSelector(selector),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	extra := &fakeTool{def: ToolDefinition{Name: "zeta"}}
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("hola"),
+		WithDisabledTools("crm"), WithExtraTools(extra)); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if strings.Join(offered, ",") != "billing__refund,billing__search" {
+		t.Errorf("el selector recibió herramientas inesperadas: %v", offered)
+	}
+	var names []string
+	for _, tool := range client.Requests()[0].Tools {
+		names = append(names, tool.Name)
+	}
+	if strings.Join(names, ",") != "billing__refund,zeta" {
+		t.Errorf("herramientas ofrecidas inesperadas: %v", names)
+	}
+
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("falla")); err == nil || !strings.Contains(err.Error(), "sin embeddings") {
+		t.Errorf("se esperaba el error del selector: %v", err)
+	}
+	if _, err := NewAgent(WithToolSelector(nil)); err == nil {
+		t.Error("se esperaba error por selector nulo")
+	}
+}
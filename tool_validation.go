@@ -0,0 +1,96 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+)
+
+// ArgumentValidation says whether agents validate a tool's arguments against its parameters schema.
+type ArgumentValidation int
+
+const (
+	ArgumentValidationDefault  ArgumentValidation = iota // Follow the agent's WithArgumentValidation setting.
+	ArgumentValidationEnabled                            // Always validate.
+	ArgumentValidationDisabled                           // Never validate.
+)
+
+// ArgumentValidationTool is a Tool that decides whether agents validate its arguments before
+// executing it, overriding the agent's setting. Tools created with NewTool implement it.
+type ArgumentValidationTool interface {
+	Tool
+	ArgumentValidation() ArgumentValidation
+}
+
+// WithArgumentValidation sets whether the agent validates the arguments of each tool call against
+// the tool's parameters schema before executing it; it is enabled by default. When the arguments
+// do not conform, the tool is not executed and the model receives an InvalidToolArguments result
+// listing the problems, so that it can fix the call. Tools implementing ArgumentValidationTool
+// override this setting.
+func WithArgumentValidation(enabled bool) AgentOption {
+	return func(a *agent) error {
+		a.validateArguments = enabled
+		return nil
+	}
+}
+
+// validatesArguments reports whether the arguments of tool are validated before it is executed.
+func (a *agent) validatesArguments(tool Tool) bool {
+	if v, ok := tool.(ArgumentValidationTool); ok {
+		switch v.ArgumentValidation() {
+		case ArgumentValidationEnabled:
+			return true
+		case ArgumentValidationDisabled:
+			return false
+		}
+	}
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+	return a.validateArguments
+}
+
+// checkToolArguments validates the arguments of call against the parameters schema of tool and
+// returns the result to send back to the model when they do not conform. Tools without a
+// usable schema are not checked.
+func (a *agent) checkToolArguments(ctx context.Context, tool Tool, call ToolCall) *InvalidToolArguments {
+	if !a.validatesArguments(tool) {
+		return nil
+	}
+	schema, ok := parametersSchema(tool.GetDefinition().Parameters)
+	if !ok {
+		return nil
+	}
+	args := call.Args
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+
+	var validationErr *SchemaValidationError
+	if err := ValidateJSON(schema, args); !errors.As(err, &validationErr) {
+		return nil
+	}
+	a.logger.WarnContext(ctx, "tool arguments rejected",
+		slog.String("agent", a.name),
+		slog.String("tool", call.Name),
+		slog.String("call_id", call.ID),
+		slog.Any("problems", validationErr.Problems),
+	)
+	invalid := newInvalidToolArguments(call.Name, validationErr.Problems)
+	return &invalid
+}
+
+// parametersSchema returns the JSON encoding of a tool's parameters schema, if it has one.
+func parametersSchema(parameters any) (json.RawMessage, bool) {
+	switch p := parameters.(type) {
+	case nil:
+		return nil, false
+	case json.RawMessage:
+		return p, len(p) > 0
+	}
+	schema, err := json.Marshal(parameters)
+	if err != nil || string(schema) == "null" {
+		return nil, false
+	}
+	return schema, true
+}
@@ -0,0 +1,107 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+type orderArgs struct {
+	Product  string `json:"product" description:"Producto a pedir"`
+	Quantity int    `json:"quantity" description:"Cantidad"`
+	Size     string `json:"size" description:"Talla" enum:"S,M,L"`
+}
+
+// newOrderTool crea una herramienta que registra los argumentos con que se ejecuta.
+func newOrderTool(t *testing.T, executed *[]string, options ...ToolOption) Tool {
+	t.Helper()
+	tool, err := NewTool(append([]ToolOption{
+		WithToolName("pedido"),
+		WithToolDescription("Registra un pedido"),
+		WithToolSchema(orderArgs{}),
+		WithToolExecuteHandler(func(args json.RawMessage) (interface{}, error) {
+			*executed = append(*executed, string(args))
+			return "ok", nil
+		}),
+	}, options...)...)
+	if err != nil {
+		t.Fatalf("error creando herramienta: %v", err)
+	}
+	return tool
+}
+
+// runOrderChat ejecuta un turno donde el modelo llama a la herramienta con args y devuelve los requests.
+func runOrderChat(t *testing.T, tool Tool, args string, options ...AgentOption) []ChatCompletionRequest {
+	t.Helper()
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "c1", Name: "pedido", Args: json.RawMessage(args)}),
+		textResponse("listo"),
+	}}
+	agent, err := NewAgent(append([]AgentOption{
+		WithClient(client),
+		WithName("Agente"),
+		WithMemory(NewSimpleMemory()),
+		WithModel(openai.GPT4),
+		WithTools(tool),
+	}, options...)...)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("pide algo")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	return client.Requests()
+}
+
+// TestToolArgumentValidation verifica que los argumentos inválidos no lleguen a la herramienta y que
+// el modelo reciba los problemas encontrados.
+func TestToolArgumentValidation(t *testing.T) {
+	var executed []string
+	requests := runOrderChat(t, newOrderTool(t, &executed), `{"product":"polera","quantity":"dos","size":"XL"}`)
+
+	if len(executed) != 0 {
+		t.Errorf("la herramienta no debía ejecutarse: %v", executed)
+	}
+	messages := requests[1].Messages
+	feedback := messages[len(messages)-1]
+	var invalid InvalidToolArguments
+	if err := json.Unmarshal([]byte(feedback.Content), &invalid); err != nil {
+		t.Fatalf("resultado no estructurado: %q", feedback.Content)
+	}
+	problems := strings.Join(invalid.Problems, "\n")
+	if invalid.Error != "invalid_arguments" || invalid.Tool != "pedido" ||
+		!strings.Contains(problems, "$.quantity") || !strings.Contains(problems, "$.size") {
+		t.Errorf("problemas inesperados: %+v", invalid)
+	}
+
+	runOrderChat(t, newOrderTool(t, &executed), `{"product":"polera","quantity":2,"size":"M"}`)
+	if len(executed) != 1 {
+		t.Errorf("los argumentos válidos debían ejecutar la herramienta: %v", executed)
+	}
+}
+
+// TestToolArgumentValidationSettings verifica la configuración por agente y por herramienta.
+func TestToolArgumentValidationSettings(t *testing.T) {
+	invalid := `{"product":"polera"}`
+
+	var executed []string
+	runOrderChat(t, newOrderTool(t, &executed), invalid, WithArgumentValidation(false))
+	if len(executed) != 1 {
+		t.Errorf("sin validación la herramienta debía ejecutarse: %v", executed)
+	}
+
+	executed = nil
+	runOrderChat(t, newOrderTool(t, &executed, WithToolArgumentValidation(false)), invalid)
+	if len(executed) != 1 {
+		t.Errorf("la herramienta desactivó la validación y debía ejecutarse: %v", executed)
+	}
+
+	executed = nil
+	runOrderChat(t, newOrderTool(t, &executed, WithToolArgumentValidation(true)), invalid, WithArgumentValidation(false))
+	if len(executed) != 0 {
+		t.Errorf("la herramienta activó la validación y no debía ejecutarse: %v", executed)
+	}
+}
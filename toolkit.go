@@ -0,0 +1,165 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ToolNamespaceSeparator joins the name of a toolkit and the name of one of its tools in the name
+// the tool is registered under, such as "billing__get_invoice".
+const ToolNamespaceSeparator = "__"
+
+// maxToolNameLength is the longest tool name accepted by the providers.
+const maxToolNameLength = 64
+
+// toolkitNamePattern matches the names allowed for toolkits.
+var toolkitNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// Toolkit is a named group of related tools.
+type Toolkit struct {
+	Name        string // Namespace of the tools; letters, digits, '_' and '-'.
+	Description string
+	Tools       []Tool
+}
+
+// ToolRegistry holds toolkits and exposes their tools under namespaced names, so that tools with
+// the same name in different toolkits never clash. It is safe for concurrent use.
+type ToolRegistry struct {
+	mutex    sync.RWMutex
+	toolkits map[string]Toolkit // Keyed by name; the tools are already namespaced.
+}
+
+// NewToolRegistry creates an empty tool registry.
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{toolkits: make(map[string]Toolkit)}
+}
+
+// Register adds a toolkit to the registry. Each of its tools is exposed as
+// "<toolkit>__<tool>"; calls to it are passed to the original tool unchanged.
+func (r *ToolRegistry) Register(toolkit Toolkit) error {
+	if !toolkitNamePattern.MatchString(toolkit.Name) || strings.Contains(toolkit.Name, ToolNamespaceSeparator) {
+		return fmt.Errorf("invalid toolkit name %q", toolkit.Name)
+	}
+	if len(toolkit.Tools) == 0 {
+		return fmt.Errorf("toolkit %s has no tools", toolkit.Name)
+	}
+
+	tools := make([]Tool, 0, len(toolkit.Tools))
+	seen := make(map[string]bool, len(toolkit.Tools))
+	for _, tool := range toolkit.Tools {
+		name, err := validateTool(tool)
+		if err != nil {
+			return fmt.Errorf("toolkit %s: %w", toolkit.Name, err)
+		}
+		if seen[name] {
+			return fmt.Errorf("toolkit %s: duplicate tool %s", toolkit.Name, name)
+		}
+		seen[name] = true
+
+		namespaced := toolkit.Name + ToolNamespaceSeparator + name
+		if len(namespaced) > maxToolNameLength {
+			return fmt.Errorf("toolkit %s: tool name %s exceeds %d characters", toolkit.Name, namespaced, maxToolNameLength)
+		}
+		tools = append(tools, &namespacedTool{tool: tool, name: namespaced})
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, exists := r.toolkits[toolkit.Name]; exists {
+		return fmt.Errorf("toolkit %s is already registered", toolkit.Name)
+	}
+	toolkit.Tools = tools
+	r.toolkits[toolkit.Name] = toolkit
+	return nil
+}
+
+// Toolkit returns the toolkit registered under name, with its tools namespaced.
+func (r *ToolRegistry) Toolkit(name string) (Toolkit, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	toolkit, ok := r.toolkits[name]
+	toolkit.Tools = append([]Tool(nil), toolkit.Tools...)
+	return toolkit, ok
+}
+
+// Toolkits returns the names of the registered toolkits, sorted.
+func (r *ToolRegistry) Toolkits() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	names := make([]string, 0, len(r.toolkits))
+	for name := range r.toolkits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Tools returns the namespaced tools of the named toolkits, or of every toolkit when no name is given.
+func (r *ToolRegistry) Tools(toolkits ...string) ([]Tool, error) {
+	if len(toolkits) == 0 {
+		toolkits = r.Toolkits()
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	var tools []Tool
+	for _, name := range toolkits {
+		toolkit, ok := r.toolkits[name]
+		if !ok {
+			return nil, fmt.Errorf("toolkit %s not found", name)
+		}
+		tools = append(tools, toolkit.Tools...)
+	}
+	return tools, nil
+}
+
+// WithToolkits registers the tools of the named toolkits of registry, or of all of them when no
+// name is given, under their namespaced names. The tools are added when the agent is created;
+// toolkits registered later are not picked up.
+func WithToolkits(registry *ToolRegistry, toolkits ...string) AgentOption {
+	return func(a *agent) error {
+		if registry == nil {
+			return errors.New("tool registry cannot be nil")
+		}
+		tools, err := registry.Tools(toolkits...)
+		if err != nil {
+			return err
+		}
+		return WithTools(tools...)(a)
+	}
+}
+
+// namespacedTool exposes a toolkit's tool under its namespaced name.
+type namespacedTool struct {
+	tool Tool
+	name string
+}
+
+func (t *namespacedTool) GetDefinition() ToolDefinition {
+	def := t.tool.GetDefinition()
+	def.Name = t.name
+	return def
+}
+
+func (t *namespacedTool) Execute(args json.RawMessage) (interface{}, error) {
+	return t.tool.Execute(args)
+}
+
+func (t *namespacedTool) ExecuteContext(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	if contextTool, ok := t.tool.(ContextTool); ok {
+		return contextTool.ExecuteContext(ctx, args)
+	}
+	return t.tool.Execute(args)
+}
+
+func (t *namespacedTool) ArgumentValidation() ArgumentValidation {
+	if v, ok := t.tool.(ArgumentValidationTool); ok {
+		return v.ArgumentValidation()
+	}
+	return ArgumentValidationDefault
+}
@@ -0,0 +1,158 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+// contextEchoTool implementa ContextTool y desactiva la validación de argumentos.
+type contextEchoTool struct {
+	name string
+}
+
+func (t *contextEchoTool) GetDefinition() ToolDefinition {
+	return ToolDefinition{Name: t.name, Description: "Devuelve los argumentos"}
+}
+
+func (t *contextEchoTool) Execute(args json.RawMessage) (interface{}, error) {
+	return "sin contexto", nil
+}
+
+func (t *contextEchoTool) ExecuteContext(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	return "con contexto", nil
+}
+
+func (t *contextEchoTool) ArgumentValidation() ArgumentValidation {
+	return ArgumentValidationDisabled
+}
+
+// newTestRegistry crea un registro con dos toolkits que comparten nombres de herramientas.
+func newTestRegistry(t *testing.T) *ToolRegistry {
+	t.Helper()
+	registry := NewToolRegistry()
+	toolkits := []Toolkit{
+		{Name: "billing", Description: "Facturación", Tools: []Tool{
+			&fakeTool{def: ToolDefinition{Name: "search", Description: "Busca facturas"}},
+			&fakeTool{def: ToolDefinition{Name: "refund", Description: "Reembolsa un pago"}},
+		}},
+		{Name: "crm", Description: "Clientes", Tools: []Tool{
+			&fakeTool{def: ToolDefinition{Name: "search", Description: "Busca clientes"}},
+			&contextEchoTool{name: "echo"},
+		}},
+	}
+	for _, toolkit := range toolkits {
+		if err := registry.Register(toolkit); err != nil {
+			t.Fatalf("error registrando toolkit: %v", err)
+		}
+	}
+	return registry
+}
+
+// TestToolRegistry verifica el registro de toolkits, los nombres con espacio de nombres y los errores.
+func TestToolRegistry(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	if names := registry.Toolkits(); strings.Join(names, ",") != "billing,crm" {
+		t.Errorf("toolkits inesperados: %v", names)
+	}
+	tools, err := registry.Tools()
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.GetDefinition().Name)
+	}
+	if strings.Join(names, ",") != "billing__search,billing__refund,crm__search,crm__echo" {
+		t.Errorf("nombres inesperados: %v", names)
+	}
+
+	crm, ok := registry.Toolkit("crm")
+	if !ok || crm.Description != "Clientes" || len(crm.Tools) != 2 {
+		t.Fatalf("toolkit inesperado: %+v", crm)
+	}
+	echo := crm.Tools[1].(ContextTool)
+	if result, _ := echo.ExecuteContext(context.Background(), nil); result != "con contexto" {
+		t.Errorf("el contexto debía propagarse a la herramienta original: %v", result)
+	}
+	if v := echo.(ArgumentValidationTool).ArgumentValidation(); v != ArgumentValidationDisabled {
+		t.Errorf("la configuración de validación debía conservarse: %v", v)
+	}
+
+	if _, err := registry.Tools("ventas"); err == nil {
+		t.Error("se esperaba error por toolkit inexistente")
+	}
+	invalid := []Toolkit{
+		{Name: "billing", Tools: []Tool{&fakeTool{def: ToolDefinition{Name: "otra"}}}},
+		{Name: "con espacio", Tools: []Tool{&fakeTool{def: ToolDefinition{Name: "a"}}}},
+		{Name: "doble__guion", Tools: []Tool{&fakeTool{def: ToolDefinition{Name: "a"}}}},
+		{Name: "vacio"},
+		{Name: "repetido", Tools: []Tool{&fakeTool{def: ToolDefinition{Name: "a"}}, &fakeTool{def: ToolDefinition{Name: "a"}}}},
+		{Name: "largo", Tools: []Tool{&fakeTool{def: ToolDefinition{Name: strings.Repeat("x", 60)}}}},
+	}
+	for _, toolkit := range invalid {
+		if err := registry.Register(toolkit); err == nil {
+			t.Errorf("se esperaba error registrando %q", toolkit.Name)
+		}
+	}
+}
+
+// TestToolkitsInAgent verifica que los agentes usen toolkits y que las opciones de chat filtren herramientas.
+func TestToolkitsInAgent(t *testing.T) {
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		textResponse("uno"), textResponse("dos"), textResponse("tres"),
+		toolCallResponse(ToolCall{ID: "c1", Name: "billing__refund", Args: json.RawMessage(`{}`)}),
+	}}
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("Agente"),
+		WithMemory(NewSimpleMemory()),
+		WithModel(openai.GPT4),
+		WithToolkits(newTestRegistry(t)),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+
+	chats := [][]ChatOption{
+		nil,
+		{WithEnabledTools("crm", "billing__refund")},
+		{WithDisabledTools("billing")},
+		{WithDisabledTools("billing__refund")},
+	}
+	want := []string{
+		"billing__refund,billing__search,crm__echo,crm__search",
+		"billing__refund,crm__echo,crm__search",
+		"crm__echo,crm__search",
+	}
+	for i, options := range chats {
+		_, err := agent.Chat(context.Background(), append([]ChatOption{WithUserName("user"), WithInput("hola")}, options...)...)
+		if i < len(want) {
+			if err != nil {
+				t.Fatalf("error inesperado: %v", err)
+			}
+			var names []string
+			for _, tool := range client.Requests()[i].Tools {
+				names = append(names, tool.Name)
+			}
+			if strings.Join(names, ",") != want[i] {
+				t.Errorf("chat %d: herramientas inesperadas: %v", i, names)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), "billing__refund not found") {
+			t.Errorf("no debía ejecutarse una herramienta desactivada: %v", err)
+		}
+	}
+
+	if _, err := NewAgent(WithToolkits(nil)); err == nil {
+		t.Error("se esperaba error por registro nulo")
+	}
+	if _, err := NewAgent(WithToolkits(NewToolRegistry(), "ventas")); err == nil {
+		t.Error("se esperaba error por toolkit inexistente")
+	}
+}
@@ -0,0 +1,220 @@
+package syndicate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Span names emitted by agents and syndicates.
+const (
+	SpanPipeline     = "syndicate.pipeline"
+	SpanExecuteAgent = "syndicate.execute_agent"
+	SpanChat         = "agent.chat"
+	SpanLLMCall      = "llm.chat_completion"
+	SpanToolCall     = "tool.call"
+	SpanCritique     = "agent.critique"
+)
+
+// Attribute keys attached to spans.
+const (
+	AttrAgentName        = "agent.name"
+	AttrRound            = "agent.round"
+	AttrPipelineLength   = "syndicate.pipeline.length"
+	AttrModel            = "llm.model"
+	AttrFinishReason     = "llm.finish_reason"
+	AttrPromptTokens     = "llm.usage.prompt_tokens"
+	AttrCompletionTokens = "llm.usage.completion_tokens"
+	AttrTotalTokens      = "llm.usage.total_tokens"
+	AttrCachedTokens     = "llm.usage.cached_prompt_tokens"
+	AttrToolName         = "tool.name"
+	AttrToolCallID       = "tool.call_id"
+	AttrCritiqueScore    = "agent.critique.score"
+)
+
+// Attribute is a key/value pair attached to a span.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Attr is a shorthand for building an Attribute.
+func Attr(key string, value any) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span represents a single timed operation within a trace.
+type Span interface {
+	// SetAttributes adds or overwrites attributes on the span.
+	SetAttributes(attrs ...Attribute)
+	// RecordError marks the span as failed with the given error.
+	RecordError(err error)
+	// End completes the span.
+	End()
+}
+
+// Tracer creates spans. Implementations propagate the parent span through the returned context,
+// so spans started from that context are nested under it.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// noopTracer is the default Tracer; it records nothing.
+type noopTracer struct{}
+
+// noopSpan is the Span returned by noopTracer.
+type noopSpan struct{}
+
+func (noopTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) End()                             {}
+
+// NoopTracer returns a Tracer that discards all spans.
+func NoopTracer() Tracer {
+	return noopTracer{}
+}
+
+// usageAttributes converts token usage into span attributes.
+func usageAttributes(usage Usage) []Attribute {
+	return []Attribute{
+		Attr(AttrPromptTokens, usage.PromptTokens),
+		Attr(AttrCompletionTokens, usage.CompletionTokens),
+		Attr(AttrTotalTokens, usage.TotalTokens),
+		Attr(AttrCachedTokens, usage.CachedPromptTokens),
+	}
+}
+
+// SpanRecord is a finished span captured by an InMemoryTracer.
+type SpanRecord struct {
+	ID         int
+	ParentID   int // Zero when the span has no parent.
+	Name       string
+	Attributes map[string]any
+	Err        error
+	Start      time.Time
+	End        time.Time
+}
+
+// Duration returns how long the span lasted.
+func (r SpanRecord) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// InMemoryTracer is a Tracer that keeps finished spans in memory. It is intended for tests.
+type InMemoryTracer struct {
+	mutex  sync.Mutex
+	nextID int
+	spans  []SpanRecord
+}
+
+// inMemorySpanKey is the context key holding the current in-memory span ID.
+type inMemorySpanKey struct{}
+
+// inMemorySpan is the Span returned by InMemoryTracer.
+type inMemorySpan struct {
+	tracer *InMemoryTracer
+	mutex  sync.Mutex
+	record SpanRecord
+	ended  bool
+}
+
+// NewInMemoryTracer creates an empty InMemoryTracer.
+func NewInMemoryTracer() *InMemoryTracer {
+	return &InMemoryTracer{}
+}
+
+// Start begins a span nested under the span stored in ctx, if any.
+func (t *InMemoryTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	t.mutex.Lock()
+	t.nextID++
+	id := t.nextID
+	t.mutex.Unlock()
+
+	parentID, _ := ctx.Value(inMemorySpanKey{}).(int)
+	span := &inMemorySpan{
+		tracer: t,
+		record: SpanRecord{
+			ID:         id,
+			ParentID:   parentID,
+			Name:       name,
+			Attributes: make(map[string]any),
+			Start:      time.Now(),
+		},
+	}
+	span.SetAttributes(attrs...)
+	return context.WithValue(ctx, inMemorySpanKey{}, id), span
+}
+
+// Spans returns the finished spans in the order they ended.
+func (t *InMemoryTracer) Spans() []SpanRecord {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	spans := make([]SpanRecord, len(t.spans))
+	copy(spans, t.spans)
+	return spans
+}
+
+// Reset discards all recorded spans.
+func (t *InMemoryTracer) Reset() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.spans = nil
+}
+
+func (s *inMemorySpan) SetAttributes(attrs ...Attribute) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, attr := range attrs {
+		s.record.Attributes[attr.Key] = attr.Value
+	}
+}
+
+func (s *inMemorySpan) RecordError(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.record.Err = err
+}
+
+func (s *inMemorySpan) End() {
+	s.mutex.Lock()
+	if s.ended {
+		s.mutex.Unlock()
+		return
+	}
+	s.ended = true
+	s.record.End = time.Now()
+	record := s.record
+	s.mutex.Unlock()
+
+	s.tracer.mutex.Lock()
+	s.tracer.spans = append(s.tracer.spans, record)
+	s.tracer.mutex.Unlock()
+}
+
+// WithTracer sets the tracer used to emit spans for chats, LLM calls and tool calls.
+func WithTracer(tracer Tracer) AgentOption {
+	return func(a *agent) error {
+		if tracer == nil {
+			return errors.New("tracer cannot be nil")
+		}
+		a.tracer = tracer
+		return nil
+	}
+}
+
+// WithSyndicateTracer sets the tracer used to emit spans for pipelines and agent executions.
+func WithSyndicateTracer(tracer Tracer) SyndicateOption {
+	return func(s *syndicate) error {
+		if tracer == nil {
+			return fmt.Errorf("tracer cannot be nil")
+		}
+		s.tracer = tracer
+		return nil
+	}
+}
@@ -0,0 +1,118 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// TestInMemoryTracerNesting verifica que los spans de pipeline, agente, chat, LLM y herramientas queden anidados.
+func TestInMemoryTracerNesting(t *testing.T) {
+	tracer := NewInMemoryTracer()
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "c1", Name: "lookup", Args: json.RawMessage(`{}`)}),
+		{
+			Choices: []Choice{{Message: Message{Content: "done"}, FinishReason: FinishReasonStop}},
+			Usage:   Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+		},
+	}}
+	tool := &fakeTool{def: ToolDefinition{Name: "lookup"}}
+
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("traced"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithTool(tool),
+		WithTracer(tracer),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+	s, err := NewSyndicate(
+		WithAgent(agent),
+		WithPipeline("traced"),
+		WithSyndicateTracer(tracer),
+	)
+	if err != nil {
+		t.Fatalf("error creando syndicate: %v", err)
+	}
+
+	if _, err := s.ExecutePipeline(context.Background(),
+		WithPipelineUserName("user"),
+		WithPipelineInput("hola"),
+	); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	spans := tracer.Spans()
+	byID := make(map[int]SpanRecord)
+	byName := make(map[string][]SpanRecord)
+	for _, span := range spans {
+		byID[span.ID] = span
+		byName[span.Name] = append(byName[span.Name], span)
+	}
+
+	parentName := func(span SpanRecord) string {
+		return byID[span.ParentID].Name
+	}
+
+	if len(byName[SpanPipeline]) != 1 || byName[SpanPipeline][0].ParentID != 0 {
+		t.Fatalf("se esperaba un único span raíz de pipeline: %+v", byName[SpanPipeline])
+	}
+	if got := parentName(byName[SpanExecuteAgent][0]); got != SpanPipeline {
+		t.Errorf("execute_agent debería colgar de pipeline, cuelga de %q", got)
+	}
+	if got := parentName(byName[SpanChat][0]); got != SpanExecuteAgent {
+		t.Errorf("chat debería colgar de execute_agent, cuelga de %q", got)
+	}
+	if len(byName[SpanLLMCall]) != 2 {
+		t.Fatalf("se esperaban 2 spans de LLM, se obtuvieron %d", len(byName[SpanLLMCall]))
+	}
+	for _, span := range byName[SpanLLMCall] {
+		if parentName(span) != SpanChat {
+			t.Errorf("el span LLM debería colgar de chat, cuelga de %q", parentName(span))
+		}
+	}
+	if got := parentName(byName[SpanToolCall][0]); got != SpanChat {
+		t.Errorf("el span de herramienta debería colgar de chat, cuelga de %q", got)
+	}
+	if got := byName[SpanToolCall][0].Attributes[AttrToolName]; got != "lookup" {
+		t.Errorf("atributo tool.name inesperado: %v", got)
+	}
+
+	last := byName[SpanLLMCall][1]
+	if last.Attributes[AttrTotalTokens] != 15 || last.Attributes[AttrFinishReason] != FinishReasonStop {
+		t.Errorf("atributos del último span LLM inesperados: %v", last.Attributes)
+	}
+	if last.Attributes[AttrRound] != 2 {
+		t.Errorf("se esperaba la ronda 2, se obtuvo %v", last.Attributes[AttrRound])
+	}
+}
+
+// TestInMemoryTracerRecordsErrors verifica que los errores se registren en el span del chat.
+func TestInMemoryTracerRecordsErrors(t *testing.T) {
+	tracer := NewInMemoryTracer()
+	agent, _ := NewAgent(
+		WithClient(&fakeLLMClientWithError{}),
+		WithName("failing"),
+		WithMemory(NewSimpleMemory()),
+		WithModel("gpt-4o"),
+		WithTracer(tracer),
+	)
+
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("hola")); err == nil {
+		t.Fatal("se esperaba un error")
+	}
+
+	for _, span := range tracer.Spans() {
+		if span.Err == nil {
+			t.Errorf("el span %s debería registrar el error", span.Name)
+		}
+	}
+
+	tracer.Reset()
+	if len(tracer.Spans()) != 0 {
+		t.Error("Reset debería descartar los spans")
+	}
+}
@@ -0,0 +1,100 @@
+package syndicate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// MemoryTx is a set of memory writes that are applied together or not at all.
+// Get returns the committed messages followed by the ones staged in the transaction.
+type MemoryTx interface {
+	Memory
+	// Commit applies the staged messages to the memory.
+	Commit() error
+	// Rollback discards the staged messages.
+	Rollback() error
+}
+
+// TransactionalMemory is a Memory that supports transactions natively, for example a database-backed
+// memory. Agents use it to make chat turns atomic; other memories get their writes staged in process.
+type TransactionalMemory interface {
+	Memory
+	Begin(ctx context.Context) (MemoryTx, error)
+}
+
+// stagedTx implements MemoryTx for memories without native transactions by keeping the
+// writes in an overlay until Commit.
+type stagedTx struct {
+	*overlayMemory
+	done bool
+}
+
+// Commit adds the staged messages to the base memory in order.
+func (tx *stagedTx) Commit() error {
+	tx.mutex.Lock()
+	defer tx.mutex.Unlock()
+	if tx.done {
+		return errors.New("transaction already finished")
+	}
+	tx.done = true
+	for _, message := range tx.pending {
+		tx.base.Add(message)
+	}
+	tx.pending = nil
+	return nil
+}
+
+// Rollback discards the staged messages.
+func (tx *stagedTx) Rollback() error {
+	tx.mutex.Lock()
+	defer tx.mutex.Unlock()
+	if tx.done {
+		return errors.New("transaction already finished")
+	}
+	tx.done = true
+	tx.pending = nil
+	return nil
+}
+
+// beginTx starts a transaction on memory, natively when it supports transactions.
+func beginTx(ctx context.Context, memory Memory) (MemoryTx, error) {
+	if transactional, ok := memory.(TransactionalMemory); ok {
+		tx, err := transactional.Begin(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to begin memory transaction: %w", err)
+		}
+		return tx, nil
+	}
+	return &stagedTx{overlayMemory: newOverlayMemory(memory)}, nil
+}
+
+// finishTx commits the turn's transaction when the turn succeeded and rolls it back otherwise.
+// It returns the turn error, or the commit error when committing fails.
+func (a *agent) finishTx(ctx context.Context, tx MemoryTx, turnErr error) error {
+	if turnErr != nil {
+		if err := tx.Rollback(); err != nil {
+			a.logger.ErrorContext(ctx, "memory rollback failed",
+				slog.String("agent", a.name),
+				slog.Any("error", err),
+			)
+		}
+		return turnErr
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit memory: %w", err)
+	}
+	return nil
+}
+
+// WithAtomicTurns sets whether chat turns are atomic, which is the default. The messages of an
+// atomic turn are staged and written to memory only when the turn succeeds, so a provider or tool
+// error leaves the history as it was. Memories implementing TransactionalMemory use their own
+// transactions. When disabled, messages are written to memory as soon as they are produced.
+func WithAtomicTurns(enabled bool) AgentOption {
+	return func(a *agent) error {
+		a.atomicTurns = enabled
+		return nil
+	}
+}
@@ -0,0 +1,148 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// txTestMemory es una memoria transaccional que registra los commits y rollbacks.
+type txTestMemory struct {
+	Memory
+	commits   int
+	rollbacks int
+	commitErr error
+}
+
+func (m *txTestMemory) Begin(ctx context.Context) (MemoryTx, error) {
+	return &txTestTx{stagedTx: &stagedTx{overlayMemory: newOverlayMemory(m.Memory)}, memory: m}, nil
+}
+
+// txTestTx envuelve stagedTx para contar las operaciones.
+type txTestTx struct {
+	*stagedTx
+	memory *txTestMemory
+}
+
+func (tx *txTestTx) Commit() error {
+	tx.memory.commits++
+	if tx.memory.commitErr != nil {
+		return tx.memory.commitErr
+	}
+	return tx.stagedTx.Commit()
+}
+
+func (tx *txTestTx) Rollback() error {
+	tx.memory.rollbacks++
+	return tx.stagedTx.Rollback()
+}
+
+// newTxTestAgent crea un agente con una herramienta que falla o no según se indique.
+func newTxTestAgent(t *testing.T, mem Memory, toolErr error, options ...AgentOption) Agent {
+	t.Helper()
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "c1", Name: "save", Args: json.RawMessage(`{}`)}),
+		textResponse("guardado"),
+	}}
+	base := []AgentOption{
+		WithClient(client),
+		WithName("agent"),
+		WithMemory(mem),
+		WithModel("gpt-4o"),
+		WithTool(&fakeTool{
+			def:      ToolDefinition{Name: "save"},
+			execFunc: func(args json.RawMessage) (interface{}, error) { return "ok", toolErr },
+		}),
+	}
+	agent, err := NewAgent(append(base, options...)...)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+	return agent
+}
+
+// TestAtomicTurnRollback verifica que un turno fallido no deje mensajes en memoria.
+func TestAtomicTurnRollback(t *testing.T) {
+	mem := NewSimpleMemory()
+	mem.Add(Message{Role: RoleUser, Content: "previo", Name: "user"})
+	agent := newTxTestAgent(t, mem, errors.New("disco lleno"))
+
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("guarda")); err == nil {
+		t.Fatal("se esperaba el error de la herramienta")
+	}
+	if msgs := mem.Get(); len(msgs) != 1 || msgs[0].Content != "previo" {
+		t.Errorf("la memoria debía quedar intacta: %+v", msgs)
+	}
+}
+
+// TestAtomicTurnCommit verifica que un turno exitoso escriba todos sus mensajes en orden.
+func TestAtomicTurnCommit(t *testing.T) {
+	mem := NewSimpleMemory()
+	agent := newTxTestAgent(t, mem, nil)
+
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("guarda")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	msgs := mem.Get()
+	roles := make([]string, len(msgs))
+	for i, msg := range msgs {
+		roles[i] = msg.Role
+	}
+	if strings.Join(roles, ",") != "user,assistant,tool,assistant" {
+		t.Errorf("mensajes inesperados en memoria: %v", roles)
+	}
+}
+
+// TestNonAtomicTurn verifica que sin turnos atómicos los mensajes se escriban de inmediato.
+func TestNonAtomicTurn(t *testing.T) {
+	mem := NewSimpleMemory()
+	agent := newTxTestAgent(t, mem, errors.New("disco lleno"), WithAtomicTurns(false))
+
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("guarda")); err == nil {
+		t.Fatal("se esperaba el error de la herramienta")
+	}
+	if msgs := mem.Get(); len(msgs) != 2 {
+		t.Errorf("se esperaban el mensaje del usuario y la llamada a la herramienta: %+v", msgs)
+	}
+}
+
+// TestTransactionalMemory verifica que se usen las transacciones nativas de la memoria.
+func TestTransactionalMemory(t *testing.T) {
+	mem := &txTestMemory{Memory: NewSimpleMemory()}
+	agent := newTxTestAgent(t, mem, nil)
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("guarda")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if mem.commits != 1 || mem.rollbacks != 0 || len(mem.Get()) != 4 {
+		t.Errorf("commit nativo inesperado: commits=%d rollbacks=%d mensajes=%d", mem.commits, mem.rollbacks, len(mem.Get()))
+	}
+
+	failing := &txTestMemory{Memory: NewSimpleMemory()}
+	agent = newTxTestAgent(t, failing, errors.New("fallo"))
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("guarda")); err == nil {
+		t.Fatal("se esperaba un error")
+	}
+	if failing.rollbacks != 1 || failing.commits != 0 {
+		t.Errorf("rollback nativo inesperado: commits=%d rollbacks=%d", failing.commits, failing.rollbacks)
+	}
+
+	broken := &txTestMemory{Memory: NewSimpleMemory(), commitErr: errors.New("sin conexión")}
+	agent = newTxTestAgent(t, broken, nil)
+	_, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("guarda"))
+	if err == nil || !strings.Contains(err.Error(), "failed to commit memory") {
+		t.Errorf("se esperaba el error de commit, se obtuvo: %v", err)
+	}
+}
+
+// TestStagedTxFinishOnce verifica que una transacción no pueda terminarse dos veces.
+func TestStagedTxFinishOnce(t *testing.T) {
+	tx := &stagedTx{overlayMemory: newOverlayMemory(NewSimpleMemory())}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if err := tx.Rollback(); err == nil {
+		t.Error("se esperaba un error al terminar la transacción dos veces")
+	}
+}
@@ -0,0 +1,122 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// InvalidToolArguments is the result sent back to the model instead of running a tool whose
+// arguments could not be decoded or do not match its schema, so that the model can fix them
+// and call the tool again.
+type InvalidToolArguments struct {
+	Error    string   `json:"error"` // Always "invalid_arguments".
+	Tool     string   `json:"tool"`
+	Problems []string `json:"problems"` // What is wrong, prefixed with the path of the offending value.
+	Hint     string   `json:"hint"`
+}
+
+// newInvalidToolArguments describes the problems found in the arguments of a call to tool.
+func newInvalidToolArguments(tool string, problems []string) InvalidToolArguments {
+	return InvalidToolArguments{
+		Error:    "invalid_arguments",
+		Tool:     tool,
+		Problems: problems,
+		Hint:     "Fix the arguments so they match the tool's parameters schema and call the tool again.",
+	}
+}
+
+// argumentProblems lists why err rejected a tool's arguments.
+func argumentProblems(err error) []string {
+	var validationErr *SchemaValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Problems
+	}
+	return []string{"$: " + err.Error()}
+}
+
+// typedTool implements NewTypedTool.
+type typedTool[In, Out any] struct {
+	name        string
+	description string
+	schema      json.RawMessage
+	fn          func(ctx context.Context, input In) (Out, error)
+}
+
+// NewTypedTool creates a tool from a function taking a typed input. The parameters schema is
+// generated from In, which must be a struct, with GenerateRawSchema. Before fn is called, the
+// arguments are validated against the schema and decoded into In; when that fails, fn is not
+// called and the model receives an InvalidToolArguments result describing the problems.
+// Errors returned by fn fail the tool call as with any other tool.
+//
+// Example:
+//
+//	type WeatherInput struct {
+//		City string `json:"city" description:"City to look up"`
+//	}
+//
+//	tool, err := syndicate.NewTypedTool("GetWeather", "Get the current weather of a city",
+//		func(ctx context.Context, in WeatherInput) (string, error) {
+//			return lookupWeather(ctx, in.City)
+//		})
+func NewTypedTool[In, Out any](name, description string, fn func(ctx context.Context, input In) (Out, error)) (ContextTool, error) {
+	if name == "" {
+		return nil, errors.New("tool name is required")
+	}
+	if description == "" {
+		return nil, errors.New("tool description is required")
+	}
+	if fn == nil {
+		return nil, errors.New("tool function is required")
+	}
+
+	if kind := reflect.TypeOf((*In)(nil)).Elem().Kind(); kind != reflect.Struct {
+		return nil, fmt.Errorf("tool input must be a struct, got %s", kind)
+	}
+	var input In
+	schema, err := GenerateRawSchema(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate schema: %w", err)
+	}
+
+	return &typedTool[In, Out]{
+		name:        name,
+		description: description,
+		schema:      schema,
+		fn:          fn,
+	}, nil
+}
+
+func (t *typedTool[In, Out]) GetDefinition() ToolDefinition {
+	return ToolDefinition{
+		Name:        t.name,
+		Description: t.description,
+		Parameters:  t.schema,
+	}
+}
+
+// ArgumentValidation disables the agent's validation, since the tool validates its own arguments.
+func (t *typedTool[In, Out]) ArgumentValidation() ArgumentValidation {
+	return ArgumentValidationDisabled
+}
+
+func (t *typedTool[In, Out]) Execute(args json.RawMessage) (interface{}, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+func (t *typedTool[In, Out]) ExecuteContext(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	if err := ValidateJSON(t.schema, args); err != nil {
+		return newInvalidToolArguments(t.name, argumentProblems(err)), nil
+	}
+
+	var input In
+	if err := json.Unmarshal(args, &input); err != nil {
+		return newInvalidToolArguments(t.name, argumentProblems(err)), nil
+	}
+	return t.fn(ctx, input)
+}
@@ -0,0 +1,128 @@
+package syndicate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+type weatherInput struct {
+	City string `json:"city" description:"Ciudad a consultar"`
+	Days int    `json:"days" description:"Días de pronóstico"`
+}
+
+type weatherOutput struct {
+	Forecast string `json:"forecast"`
+}
+
+// newWeatherTool crea una herramienta tipada que registra la entrada recibida.
+func newWeatherTool(t *testing.T, received *weatherInput) ContextTool {
+	t.Helper()
+	tool, err := NewTypedTool("clima", "Consulta el clima", func(ctx context.Context, in weatherInput) (weatherOutput, error) {
+		*received = in
+		if in.City == "Atlántida" {
+			return weatherOutput{}, errors.New("ciudad inexistente")
+		}
+		return weatherOutput{Forecast: "soleado en " + in.City}, nil
+	})
+	if err != nil {
+		t.Fatalf("error creando herramienta: %v", err)
+	}
+	return tool
+}
+
+// TestTypedToolExecute verifica la decodificación de argumentos y los errores estructurados.
+func TestTypedToolExecute(t *testing.T) {
+	var received weatherInput
+	tool := newWeatherTool(t, &received)
+
+	def := tool.GetDefinition()
+	schema, _ := json.Marshal(def.Parameters)
+	if def.Name != "clima" || !strings.Contains(string(schema), `"city"`) || !strings.Contains(string(schema), `"required":["city","days"]`) {
+		t.Errorf("definición inesperada: %+v %s", def, schema)
+	}
+
+	result, err := tool.ExecuteContext(context.Background(), json.RawMessage(`{"city":"Lima","days":3}`))
+	if err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if out, ok := result.(weatherOutput); !ok || out.Forecast != "soleado en Lima" || received.Days != 3 {
+		t.Errorf("resultado inesperado: %+v %+v", result, received)
+	}
+
+	received = weatherInput{}
+	result, err = tool.Execute(json.RawMessage(`{"city":3,"extra":true}`))
+	if err != nil {
+		t.Fatalf("los argumentos inválidos no deben fallar la llamada: %v", err)
+	}
+	invalid, ok := result.(InvalidToolArguments)
+	if !ok || invalid.Error != "invalid_arguments" || invalid.Tool != "clima" || len(invalid.Problems) < 3 {
+		t.Fatalf("resultado inesperado: %+v", result)
+	}
+	if received != (weatherInput{}) {
+		t.Errorf("la función no debía ejecutarse: %+v", received)
+	}
+
+	result, _ = tool.Execute(json.RawMessage(`{"city":`))
+	if invalid, ok := result.(InvalidToolArguments); !ok || !strings.Contains(invalid.Problems[0], "invalid JSON") {
+		t.Errorf("se esperaba error de JSON inválido: %+v", result)
+	}
+
+	if _, err := tool.Execute(json.RawMessage(`{"city":"Atlántida","days":1}`)); err == nil || err.Error() != "ciudad inexistente" {
+		t.Errorf("se esperaba el error de la función: %v", err)
+	}
+}
+
+// TestNewTypedToolValidation verifica la validación de los parámetros del constructor.
+func TestNewTypedToolValidation(t *testing.T) {
+	fn := func(ctx context.Context, in weatherInput) (string, error) { return "", nil }
+	if _, err := NewTypedTool("", "desc", fn); err == nil {
+		t.Error("se esperaba error por nombre vacío")
+	}
+	if _, err := NewTypedTool("clima", "", fn); err == nil {
+		t.Error("se esperaba error por descripción vacía")
+	}
+	if _, err := NewTypedTool[weatherInput, string]("clima", "desc", nil); err == nil {
+		t.Error("se esperaba error por función nula")
+	}
+	if _, err := NewTypedTool("clima", "desc", func(ctx context.Context, in string) (string, error) { return in, nil }); err == nil {
+		t.Error("se esperaba error por entrada que no es struct")
+	}
+}
+
+// TestTypedToolInAgent verifica que el modelo reciba el error estructurado y pueda corregir la llamada.
+func TestTypedToolInAgent(t *testing.T) {
+	var received weatherInput
+	client := &recordingLLMClient{responses: []ChatCompletionResponse{
+		toolCallResponse(ToolCall{ID: "c1", Name: "clima", Args: json.RawMessage(`{"city":"Lima"}`)}),
+		toolCallResponse(ToolCall{ID: "c2", Name: "clima", Args: json.RawMessage(`{"city":"Lima","days":2}`)}),
+		textResponse("Soleado"),
+	}}
+	agent, err := NewAgent(
+		WithClient(client),
+		WithName("Agente"),
+		WithMemory(NewSimpleMemory()),
+		WithModel(openai.GPT4),
+		WithTools(newWeatherTool(t, &received)),
+	)
+	if err != nil {
+		t.Fatalf("error creando agente: %v", err)
+	}
+	if _, err := agent.Chat(context.Background(), WithUserName("user"), WithInput("¿clima?")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+
+	requests := client.Requests()
+	feedback := requests[1].Messages[len(requests[1].Messages)-1]
+	if feedback.Role != RoleTool || !strings.Contains(feedback.Content, `"error":"invalid_arguments"`) ||
+		!strings.Contains(feedback.Content, "days") {
+		t.Errorf("retroalimentación inesperada: %+v", feedback)
+	}
+	if received.Days != 2 {
+		t.Errorf("la llamada corregida debía ejecutarse: %+v", received)
+	}
+}